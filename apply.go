@@ -0,0 +1,370 @@
+package gosqlshell
+
+import (
+	"errors"
+	"reflect"
+)
+
+// ApplyFunc is invoked by Apply for each node n, even if n is nil, before
+// and/or after the node's children, using a Cursor describing the current
+// node and providing operations on it.
+//
+// The return value of ApplyFunc controls the syntax tree traversal, see
+// Apply for details.
+type ApplyFunc func(*Cursor) bool
+
+// Apply traverses the tree under root recursively, calling pre and post for
+// each node in the order Walk visits them, as well as for nil nodes of
+// optional fields such as a missing WHERE, so that they can be filled in.
+//
+// If pre is not nil, it is called for each node before the node's children
+// are traversed. If pre returns false, no children are traversed, and post
+// is not called for that node.
+//
+// If post is not nil, and a prior call of pre didn't return false, post is
+// called for each node after its children are traversed. If post returns
+// false, traversal is terminated and Apply returns immediately.
+//
+// Only nodes of the tree may be changed, through the Cursor. Replacing a
+// node by one of a type its field can't hold panics. Apply returns the
+// root, which may have been replaced.
+func Apply(root Node, pre, post ApplyFunc) (result Node) {
+	parent := &struct{ Node }{root}
+	defer func() {
+		if r := recover(); r != nil && r != abort {
+			panic(r)
+		}
+
+		result = parent.Node
+	}()
+
+	a := &application{pre: pre, post: post}
+	a.applyField(parent, "Node")
+	return
+}
+
+var abort = new(int)
+
+// A Cursor describes a node encountered during Apply. Information about the
+// node and its parent is available from the Node, Parent, Name, and Index
+// methods.
+//
+// The methods Replace, Delete, InsertBefore, and InsertAfter can be used to
+// change the tree. They are only valid during the ApplyFunc call they were
+// given to.
+type Cursor struct {
+	parent Node
+	name   string
+	iter   *iterator // valid if non-nil
+	node   Node
+	// field holding the node, or the list holding it if iter is set
+	field reflect.Value
+}
+
+// Node returns the current Node
+func (c *Cursor) Node() Node {
+	return c.node
+}
+
+// Parent returns the parent of the current Node
+func (c *Cursor) Parent() Node {
+	return c.parent
+}
+
+// Name returns the name of the parent field holding the current Node, e.g.
+// Where. For the statement of a *Statement it's the name of its type.
+func (c *Cursor) Name() string {
+	return c.name
+}
+
+// Index reports the index of the current Node in the list of its parent,
+// or a value < 0 if it's not part of a list. The index of values of INSERT
+// statements is within their row.
+func (c *Cursor) Index() int {
+	if c.iter != nil {
+		return c.iter.index
+	}
+
+	return -1
+}
+
+// Replace replaces the current Node with n, which may be nil for optional
+// fields
+func (c *Cursor) Replace(n Node) {
+	value := reflect.Zero(c.field.Type())
+	if c.iter != nil {
+		value = reflect.Zero(c.field.Type().Elem())
+	}
+
+	if n != nil {
+		value = reflect.ValueOf(n)
+	}
+
+	if c.iter != nil {
+		c.field.Index(c.iter.index).Set(value)
+	} else {
+		c.field.Set(value)
+	}
+
+	c.node = n
+}
+
+// Delete deletes the current Node from its containing list. It panics if
+// the node is not part of a list.
+func (c *Cursor) Delete() {
+	l := c.list("Delete")
+	i := c.iter.index
+	reflect.Copy(l.Slice(i, l.Len()), l.Slice(i+1, l.Len()))
+	l.Index(l.Len() - 1).Set(reflect.Zero(l.Type().Elem()))
+	l.SetLen(l.Len() - 1)
+	c.iter.step--
+}
+
+// InsertAfter inserts n after the current Node in its containing list. It
+// panics if the node is not part of a list. The inserted node is not
+// walked by Apply.
+func (c *Cursor) InsertAfter(n Node) {
+	l := c.list("InsertAfter")
+	i := c.iter.index
+	l.Set(reflect.Append(l, reflect.Zero(l.Type().Elem())))
+	reflect.Copy(l.Slice(i+2, l.Len()), l.Slice(i+1, l.Len()))
+	l.Index(i + 1).Set(reflect.ValueOf(n))
+	c.iter.step++
+}
+
+// InsertBefore inserts n before the current Node in its containing list. It
+// panics if the node is not part of a list. The inserted node is not
+// walked by Apply.
+func (c *Cursor) InsertBefore(n Node) {
+	l := c.list("InsertBefore")
+	i := c.iter.index
+	l.Set(reflect.Append(l, reflect.Zero(l.Type().Elem())))
+	reflect.Copy(l.Slice(i+1, l.Len()), l.Slice(i, l.Len()))
+	l.Index(i).Set(reflect.ValueOf(n))
+	c.iter.index++
+}
+
+func (c *Cursor) list(op string) reflect.Value {
+	if c.iter == nil {
+		panic(op + " node not contained in a list")
+	}
+
+	return c.field
+}
+
+type iterator struct {
+	index, step int
+}
+
+type application struct {
+	pre, post ApplyFunc
+	cursor    Cursor
+	iter      iterator
+}
+
+// nodeOf is the node held by v, nil pointers giving a nil Node
+func nodeOf(v reflect.Value) Node {
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return nil
+	}
+
+	return v.Interface().(Node)
+}
+
+func (a *application) applyField(parent Node, name string) {
+	a.apply(parent, name, nil, reflect.ValueOf(parent).Elem().FieldByName(name))
+}
+
+func (a *application) applyList(parent Node, name string) {
+	a.applyItems(parent, name, reflect.ValueOf(parent).Elem().FieldByName(name))
+}
+
+func (a *application) applyItems(parent Node, name string, list reflect.Value) {
+	saved := a.iter
+	a.iter.index = 0
+	for a.iter.index < list.Len() {
+		a.iter.step = 1
+		a.apply(parent, name, &a.iter, list)
+		a.iter.index += a.iter.step
+	}
+	a.iter = saved
+}
+
+func (a *application) apply(parent Node, name string, iter *iterator, field reflect.Value) {
+	var n Node
+	if iter != nil {
+		n = nodeOf(field.Index(iter.index))
+	} else {
+		n = nodeOf(field)
+	}
+
+	// the cursor is reused rather than allocated for each node
+	saved := a.cursor
+	a.cursor = Cursor{parent: parent, name: name, iter: iter, node: n, field: field}
+
+	if a.pre != nil && !a.pre(&a.cursor) {
+		a.cursor = saved
+		return
+	}
+
+	switch n := a.cursor.node.(type) {
+	case nil:
+		// nothing to do
+	case *Ast:
+		a.applyList(n, "Statements")
+	case *Statement:
+		// a statement of unknown kind has no node to visit
+		if stmt := reflect.ValueOf(n.node()); stmt.Kind() == reflect.Ptr {
+			a.applyField(n, stmt.Type().Elem().Name())
+		}
+
+	// statements
+	case *SelectStatement:
+		a.applyList(n, "Items")
+		a.applyField(n, "From")
+		a.applyField(n, "Where")
+		a.applyField(n, "Limit")
+	case *CreateTableStatement:
+		a.applyField(n, "Name")
+		a.applyList(n, "Columns")
+	case *CreateIndexStatement:
+		a.applyField(n, "Name")
+		a.applyField(n, "Table")
+		a.applyField(n, "Method")
+		a.applyList(n, "Elements")
+		a.applyList(n, "Include")
+		a.applyField(n, "Where")
+	case *DropIndexStatement:
+		a.applyField(n, "Name")
+	case *DropTableStatement:
+		a.applyField(n, "Name")
+	case *CreateViewStatement:
+		a.applyField(n, "Name")
+		a.applyList(n, "Columns")
+		a.applyField(n, "Query")
+	case *RefreshMaterializedViewStatement:
+		a.applyField(n, "Name")
+	case *DropViewStatement:
+		a.applyField(n, "Name")
+	case *InsertStatement:
+		a.applyField(n, "Table")
+		a.applyList(n, "Columns")
+		for i := range n.Values {
+			a.applyItems(n, "Values", reflect.ValueOf(n.Values).Index(i))
+		}
+
+		a.applyField(n, "Query")
+		a.applyField(n, "OnConflict")
+		a.applyList(n, "OnDuplicateKey")
+		a.applyList(n, "Returning")
+	case *UpdateStatement:
+		a.applyField(n, "Table")
+		a.applyList(n, "Set")
+		a.applyField(n, "Where")
+		a.applyList(n, "Returning")
+	case *DeleteStatement:
+		a.applyField(n, "Table")
+		a.applyField(n, "Where")
+		a.applyList(n, "Returning")
+	case *BeginStatement, *CommitStatement:
+		// nothing to do
+	case *RollbackStatement:
+		a.applyField(n, "Savepoint")
+	case *SavepointStatement:
+		a.applyField(n, "Name")
+	case *ReleaseSavepointStatement:
+		a.applyField(n, "Name")
+
+	// clauses
+	case *SelectItem:
+		if !n.Asterisk {
+			a.applyField(n, "Expression")
+		}
+
+		a.applyField(n, "Alias")
+	case *TableReference:
+		a.applyField(n, "Name")
+		a.applyField(n, "Alias")
+	case *LimitClause:
+		if n.Comma {
+			a.applyField(n, "Offset")
+			a.applyField(n, "Count")
+		} else {
+			a.applyField(n, "Count")
+			a.applyField(n, "Offset")
+		}
+	case *ColumnDefinition:
+		a.applyField(n, "Name")
+	case *IndexElement:
+		a.applyField(n, "Expression")
+	case *SetClause:
+		a.applyField(n, "Column")
+		a.applyField(n, "Value")
+	case *OnConflictClause:
+		a.applyList(n, "Columns")
+		a.applyField(n, "Constraint")
+		a.applyList(n, "Set")
+		a.applyField(n, "Where")
+
+	// expressions
+	case *QualifiedName:
+		a.applyList(n, "Parts")
+	case *BinaryExpression:
+		a.applyField(n, "Left")
+		a.applyField(n, "Right")
+	case *TypedLiteral:
+		a.applyField(n, "Value")
+	case *ValuesExpression:
+		a.applyField(n, "Column")
+	case *InExpression:
+		a.applyField(n, "Expression")
+		a.applyList(n, "List")
+	case *Identifier, *Literal, *ParameterExpression:
+		// nothing to do
+	}
+
+	if a.post != nil && !a.post(&a.cursor) {
+		panic(abort)
+	}
+
+	a.cursor = saved
+}
+
+// AddWhereConjunct restricts the rows a statement works on to those also
+// matching exp, ANDing it to the current WHERE clause if there is one. It
+// applies to select, update and delete statements, and to the queries of
+// views and INSERT ... SELECT.
+func AddWhereConjunct(stmt *Statement, exp Expression) error {
+	var where *Expression
+	switch stmt.Kind {
+	case SelectKind:
+		where = &stmt.SelectStatement.Where
+	case UpdateKind:
+		where = &stmt.UpdateStatement.Where
+	case DeleteKind:
+		where = &stmt.DeleteStatement.Where
+	case CreateViewKind:
+		where = &stmt.CreateViewStatement.Query.Where
+	case InsertKind:
+		if stmt.InsertStatement.Query == nil {
+			return errors.New("Insert statement has no query to add a WHERE clause to")
+		}
+
+		where = &stmt.InsertStatement.Query.Where
+	default:
+		return errors.New("Statement has no WHERE clause")
+	}
+
+	if *where == nil {
+		*where = exp
+		return nil
+	}
+
+	*where = &BinaryExpression{
+		Left:     *where,
+		Right:    exp,
+		Operator: string(andKeyword),
+	}
+
+	return nil
+}
@@ -0,0 +1,271 @@
+package gosqlshell
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		source string
+		pre    ApplyFunc
+		post   ApplyFunc
+		result string
+	}{
+		{
+			// rename a table everywhere but in column references
+			source: "SELECT users.id FROM users WHERE id = 1",
+			pre: func(c *Cursor) bool {
+				if id, ok := c.Node().(*Identifier); ok && id.Name == "users" {
+					if _, ok := c.Parent().(*QualifiedName); ok && c.Index() == 0 {
+						c.Replace(&Identifier{Name: "accounts"})
+					}
+				}
+
+				return true
+			},
+			result: `SELECT
+	accounts.id
+FROM
+	accounts
+WHERE
+	(id = 1);`,
+		},
+		{
+			// fill in a missing LIMIT
+			source: "SELECT id FROM users",
+			pre: func(c *Cursor) bool {
+				if _, ok := c.Parent().(*SelectStatement); ok && c.Name() == "Limit" && c.Node() == nil {
+					c.Replace(&LimitClause{Count: &Literal{Kind: NumericLiteral, Value: "10"}})
+				}
+
+				return true
+			},
+			result: `SELECT
+	id
+FROM
+	users
+LIMIT 10;`,
+		},
+		{
+			// drop a select item and add one after another
+			source: "SELECT id, password, name FROM users",
+			pre: func(c *Cursor) bool {
+				item, ok := c.Node().(*SelectItem)
+				if !ok {
+					return true
+				}
+
+				switch item.Expression.(*Identifier).Name {
+				case "password":
+					c.Delete()
+				case "name":
+					c.InsertAfter(&SelectItem{Expression: &Identifier{Name: "email"}})
+				}
+
+				return false
+			},
+			result: `SELECT
+	id,
+	name,
+	email
+FROM
+	users;`,
+		},
+		{
+			// remove a WHERE clause and insert a value ahead of another
+			source: "UPDATE users SET name = 'x' WHERE id = 1",
+			pre: func(c *Cursor) bool {
+				if c.Name() == "Where" {
+					c.Replace(nil)
+				}
+
+				if sc, ok := c.Node().(*SetClause); ok {
+					c.InsertBefore(&SetClause{Column: &Identifier{Name: "updated"}, Value: &Literal{Kind: BoolLiteral, Value: "true"}})
+					sc.Value = &Literal{Kind: StringLiteral, Value: "y"}
+				}
+
+				return true
+			},
+			result: `UPDATE users SET updated = true, name = 'y';`,
+		},
+		{
+			// rewrite bottom up, replacing comparisons of constants by their
+			// result
+			source: "DELETE FROM users WHERE 1 = 1",
+			post: func(c *Cursor) bool {
+				be, ok := c.Node().(*BinaryExpression)
+				if !ok {
+					return true
+				}
+
+				left, lok := be.Left.(*Literal)
+				right, rok := be.Right.(*Literal)
+				if lok && rok && be.Operator == "=" {
+					c.Replace(&Literal{Kind: BoolLiteral, Value: "true"})
+					if left.Value != right.Value {
+						c.Replace(&Literal{Kind: BoolLiteral, Value: "false"})
+					}
+				}
+
+				return true
+			},
+			result: `DELETE FROM users WHERE true;`,
+		},
+		{
+			// stop at the first parameter
+			source: "INSERT INTO users (id, name) VALUES ($1, $2)",
+			post: func(c *Cursor) bool {
+				if _, ok := c.Node().(*ParameterExpression); ok {
+					c.Replace(&Literal{Kind: NullLiteral, Value: "NULL"})
+					return false
+				}
+
+				return true
+			},
+			result: `INSERT INTO users (id, name) VALUES (NULL, $2);`,
+		},
+	}
+
+	for _, test := range tests {
+		parser := Parser{HelpMessagesDisabled: true}
+		ast, err := parser.Parse(test.source)
+		if !assert.Nil(t, err, test.source) {
+			continue
+		}
+
+		assert.Equal(t, ast, Apply(ast, test.pre, test.post), test.source)
+		assert.Equal(t, test.result, ast.Statements[0].GenerateCode(), test.source)
+	}
+}
+
+func TestApply_root(t *testing.T) {
+	parser := Parser{HelpMessagesDisabled: true}
+	ast, err := parser.Parse("SELECT id FROM users")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	replacement := &Ast{}
+	result := Apply(ast, func(c *Cursor) bool {
+		if _, ok := c.Node().(*Ast); ok {
+			c.Replace(replacement)
+		}
+
+		return false
+	}, nil)
+
+	assert.Same(t, replacement, result)
+}
+
+func TestApply_unknownKind(t *testing.T) {
+	stmt := &Statement{Kind: AstKind(255)}
+
+	visited := []Node{}
+	result := Apply(stmt, func(c *Cursor) bool {
+		visited = append(visited, c.Node())
+		return true
+	}, nil)
+
+	assert.Same(t, stmt, result)
+	assert.Equal(t, []Node{stmt}, visited)
+}
+
+func TestApply_panics(t *testing.T) {
+	parser := Parser{HelpMessagesDisabled: true}
+	ast, err := parser.Parse("SELECT id FROM users")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Panics(t, func() {
+		Apply(ast, func(c *Cursor) bool {
+			if _, ok := c.Node().(*TableReference); ok {
+				c.Delete()
+			}
+
+			return true
+		}, nil)
+	})
+}
+
+func TestAddWhereConjunct(t *testing.T) {
+	tenant := func() Expression {
+		return &BinaryExpression{
+			Left:     &Identifier{Name: "tenant_id"},
+			Right:    &ParameterExpression{Placeholder: "$1"},
+			Operator: "=",
+		}
+	}
+
+	tests := []struct {
+		source string
+		result string
+		err    string
+	}{
+		{
+			source: "SELECT id FROM users",
+			result: `SELECT
+	id
+FROM
+	users
+WHERE
+	(tenant_id = $1);`,
+		},
+		{
+			source: "SELECT id FROM users WHERE id = 1 OR id = 2",
+			result: `SELECT
+	id
+FROM
+	users
+WHERE
+	(((id = 1) or (id = 2)) and (tenant_id = $1));`,
+		},
+		{
+			source: "UPDATE users SET name = 'x' WHERE id = 1",
+			result: `UPDATE users SET name = 'x' WHERE ((id = 1) and (tenant_id = $1));`,
+		},
+		{
+			source: "DELETE FROM users",
+			result: `DELETE FROM users WHERE (tenant_id = $1);`,
+		},
+		{
+			source: "INSERT INTO archive SELECT id FROM users",
+			result: `INSERT INTO archive
+SELECT
+	id
+FROM
+	users
+WHERE
+	(tenant_id = $1);`,
+		},
+		{
+			source: "INSERT INTO users (id) VALUES (1)",
+			err:    "Insert statement has no query to add a WHERE clause to",
+		},
+		{
+			source: "DROP TABLE users",
+			err:    "Statement has no WHERE clause",
+		},
+	}
+
+	for _, test := range tests {
+		parser := Parser{HelpMessagesDisabled: true}
+		ast, err := parser.Parse(test.source)
+		if !assert.Nil(t, err, test.source) {
+			continue
+		}
+
+		stmt := ast.Statements[0]
+		err = AddWhereConjunct(stmt, tenant())
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, test.source)
+			continue
+		}
+
+		if assert.Nil(t, err, test.source) {
+			assert.Equal(t, test.result, stmt.GenerateCode(), test.source)
+		}
+	}
+}
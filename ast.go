@@ -3,159 +3,988 @@ package gosqlshell
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
-type expressionKind uint
+// Pos is a position in the source. Lines and columns start at 1, columns
+// counting runes, so that the zero Pos of nodes built by hand is invalid.
+type Pos struct {
+	// Offset in bytes, starting at 0
+	Offset uint `json:"offset"`
+	Line   uint `json:"line"`
+	Col    uint `json:"col"`
+}
+
+// IsValid reports whether the position is known
+func (p Pos) IsValid() bool {
+	return p.Line > 0
+}
+
+// String formats the position as line:column, or - if it isn't known
+func (p Pos) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d:%d", p.Line, p.Col)
+}
+
+// Node is implemented by every node of the Ast
+type Node interface {
+	// Pos is the position of the first character of the node
+	Pos() Pos
+	// End is the position right after the last character of the node
+	End() Pos
+}
+
+// span records where a node was parsed from, nodes built by hand have none
+type span struct {
+	pos Pos
+	end Pos
+}
+
+// Pos of the first character of the node
+func (s span) Pos() Pos {
+	return s.pos
+}
+
+// End is the position right after the node
+func (s span) End() Pos {
+	return s.end
+}
+
+// Expression is implemented by the expression nodes: *Identifier,
+// *QualifiedName, *Literal, *ParameterExpression, *TypedLiteral,
+// *ValuesExpression, *BinaryExpression and *InExpression
+type Expression interface {
+	Node
+	generateCode(g *generator) string
+}
+
+// Identifier is a single name, e.g. a column, table or alias
+type Identifier struct {
+	span
+	// Name is case folded unless it was quoted
+	Name string
+	// Quoted is set for names written in quotes, e.g. "UserId"
+	Quoted bool
+}
+
+func (id *Identifier) generateCode(g *generator) string {
+	return g.generateIdentifier(id.Name)
+}
+
+// QualifiedName is a dotted reference to a table or column such as
+// catalog.schema.table, alias.column or alias.*
+type QualifiedName struct {
+	span
+	Parts    []*Identifier
+	Asterisk bool
+}
+
+func (qn *QualifiedName) generateCode(g *generator) string {
+	parts := []string{}
+	for _, part := range qn.Parts {
+		parts = append(parts, part.generateCode(g))
+	}
+
+	if qn.Asterisk {
+		parts = append(parts, "*")
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// LiteralKind tells the kind of a Literal
+type LiteralKind uint
 
 const (
-	literalKind expressionKind = iota
-	binaryKind
+	// StringLiteral is a standard string, e.g. 'foo'
+	StringLiteral LiteralKind = iota
+	// NumericLiteral is a number, e.g. -1.5
+	NumericLiteral
+	// BoolLiteral is true or false
+	BoolLiteral
+	// NullLiteral is NULL
+	NullLiteral
+	// DefaultLiteral is DEFAULT in a VALUES list
+	DefaultLiteral
+	// EscapeStringLiteral is a PostgreSQL E'...' string
+	EscapeStringLiteral
+	// DollarStringLiteral is a PostgreSQL $tag$...$tag$ string
+	DollarStringLiteral
+	// HexStringLiteral is a X'...' string
+	HexStringLiteral
+	// BitStringLiteral is a B'...' string
+	BitStringLiteral
+	// NationalStringLiteral is a N'...' string
+	NationalStringLiteral
 )
 
-type binaryExpression struct {
-	a  expression
-	b  expression
-	op token
+// Literal is a constant value
+type Literal struct {
+	span
+	Kind LiteralKind
+	// Value is the text between the quotes for strings, with quotes
+	// doubled as in a standard string, and the literal as written for
+	// anything else
+	Value string
+	// Tag of dollar quoted strings, e.g. fn for $fn$...$fn$
+	Tag string
 }
 
-func (be binaryExpression) generateCode() string {
-	return fmt.Sprintf("(%s %s %s)", be.a.generateCode(), be.op.value, be.b.generateCode())
+func (l *Literal) generateCode(g *generator) string {
+	return g.generateLiteral(l)
 }
 
-type expression struct {
-	literal *token
-	binary  *binaryExpression
-	kind    expressionKind
+// ParameterExpression is a bind parameter placeholder like $1, ?, :id or @id
+type ParameterExpression struct {
+	span
+	Placeholder string
 }
 
-func (e expression) generateCode() string {
-	switch e.kind {
-	case literalKind:
-		switch e.literal.kind {
-		case identifierKind:
-			return fmt.Sprintf("\"%s\"", e.literal.value)
-		case stringKind:
-			return fmt.Sprintf("'%s'", e.literal.value)
-		default:
-			return fmt.Sprintf(e.literal.value)
+func (pe *ParameterExpression) generateCode(g *generator) string {
+	return g.generateParameter(pe)
+}
+
+// ValuesExpression is MySQL's VALUES(column) in ON DUPLICATE KEY UPDATE,
+// the value the conflicting row would have inserted in column
+type ValuesExpression struct {
+	span
+	Column *Identifier
+}
+
+func (ve *ValuesExpression) generateCode(g *generator) string {
+	g.require("VALUES()", MySQLDialect)
+	return fmt.Sprintf("%s(%s)", g.keyword("VALUES"), ve.Column.generateCode(g))
+}
+
+// BinaryExpression is an operation on two expressions, e.g. a = 1
+type BinaryExpression struct {
+	span
+	Left  Expression
+	Right Expression
+	// Operator is the symbol, or and and or in lowercase
+	Operator string
+}
+
+func (be *BinaryExpression) generateCode(g *generator) string {
+	a, b := g.generateOperand(be.Operator, be.Left, false), g.generateOperand(be.Operator, be.Right, true)
+
+	if be.Operator == string(concatSymbol) {
+		switch g.dialect {
+		case MySQLDialect:
+			// || is a logical or in MySQL
+			return fmt.Sprintf("%s(%s, %s)", g.keyword("CONCAT"), a, b)
+		case SQLServerDialect:
+			return g.parenthesize(fmt.Sprintf("%s + %s", a, b))
 		}
+	}
 
-	case binaryKind:
-		return e.binary.generateCode()
+	operator := be.Operator
+	switch operator {
+	case string(andKeyword), string(orKeyword):
+		// long conditions are wrapped before their and and or
+		return g.parenthesize(fmt.Sprintf("%s %s%s %s", a, g.softBreak(), g.keyword(operator), b))
 	}
 
-	return ""
+	return g.parenthesize(fmt.Sprintf("%s %s %s", a, operator, b))
 }
 
-type selectItem struct {
-	exp      *expression
-	asterisk bool // for 'select * '
-	as       *token
+// InExpression tests whether an expression is one of a list, e.g.
+// a IN (1, 2) or a NOT IN (1, 2)
+type InExpression struct {
+	span
+	Expression Expression
+	Not        bool
+	List       []Expression
+}
+
+func (in *InExpression) generateCode(g *generator) string {
+	operator := g.keyword("IN")
+	if in.Not {
+		operator = g.keyword("NOT IN")
+	}
+
+	list := []string{}
+	for _, exp := range in.List {
+		list = append(list, exp.generateCode(g))
+	}
+
+	return g.parenthesize(fmt.Sprintf("%s %s (%s)", g.generateOperand(string(inKeyword), in.Expression, false), operator, g.list(list)))
+}
+
+// TypedLiteral is a date or time constant such as DATE '2024-01-01' or
+// INTERVAL 3 DAY, along with its parsed value
+type TypedLiteral struct {
+	span
+	// Type is DATE, TIME, TIMESTAMP or INTERVAL
+	Type  string
+	Value *Literal
+	// Unit of intervals like INTERVAL '3' DAY, e.g. DAY
+	Unit string
+	// Time is set for all but intervals
+	Time     time.Time
+	Interval *Interval
+}
+
+func (tl *TypedLiteral) generateCode(g *generator) string {
+	if tl.Type == "INTERVAL" {
+		return tl.generateInterval(g)
+	}
+
+	typ := tl.Type
+	value := tl.Value.generateCode(g)
+	switch g.dialect {
+	case SQLiteDialect:
+		// SQLite keeps dates and times as text
+		return value
+	case SQLServerDialect:
+		if typ == "TIMESTAMP" {
+			typ = "DATETIME2"
+		}
+
+		return fmt.Sprintf("%s(%s %s %s)", g.keyword("CAST"), value, g.keyword("AS"), g.keyword(typ))
+	}
+
+	return fmt.Sprintf("%s %s", g.keyword(typ), value)
+}
+
+func (tl *TypedLiteral) generateInterval(g *generator) string {
+	g.require("INTERVAL", PostgreSQLDialect, MySQLDialect, ANSIDialect)
+
+	// MySQL and the standard always need a unit
+	if tl.Unit == "" && (g.dialect == MySQLDialect || g.dialect == ANSIDialect) {
+		n, unit, ok := tl.Interval.singleUnit()
+		if ok && g.dialect == MySQLDialect {
+			return fmt.Sprintf("%s %d %s", g.keyword("INTERVAL"), n, g.keyword(unit))
+		} else if ok {
+			return fmt.Sprintf("%s '%d' %s", g.keyword("INTERVAL"), n, g.keyword(unit))
+		}
+
+		g.unsupported(fmt.Sprintf("INTERVAL '%s' spanning several units", tl.Value.Value))
+	}
+
+	value := tl.Value.generateCode(g)
+	if tl.Unit == "" {
+		return g.keyword("INTERVAL") + " " + value
+	}
+
+	// only MySQL takes a bare number
+	if tl.Value.Kind == NumericLiteral && g.dialect != MySQLDialect {
+		value = g.generateString("", tl.Value.Value)
+	}
+
+	return fmt.Sprintf("%s %s %s", g.keyword("INTERVAL"), value, g.keyword(tl.Unit))
+}
+
+// SelectItem is an item of the select list, or of a RETURNING clause
+type SelectItem struct {
+	span
+	Expression Expression
+	// Asterisk is set for SELECT *
+	Asterisk bool
+	Alias    *Identifier
+}
+
+func (si *SelectItem) generateCode(g *generator) string {
+	if si.Asterisk {
+		return "*"
+	}
+
+	s := si.Expression.generateCode(g)
+	if si.Alias != nil {
+		s = fmt.Sprintf("%s %s %s", s, g.keyword("AS"), si.Alias.generateCode(g))
+	}
+
+	return s
+}
+
+func (g *generator) generateReturning(items []*SelectItem) string {
+	if items == nil {
+		return ""
+	}
+
+	g.require("RETURNING", PostgreSQLDialect, SQLiteDialect)
+
+	returning := []string{}
+	for _, i := range items {
+		returning = append(returning, i.generateCode(g))
+	}
+
+	return g.space() + g.keyword("RETURNING") + " " + g.list(returning)
+}
+
+// TableReference is the table a select statement reads from
+type TableReference struct {
+	span
+	Name  *QualifiedName
+	Alias *Identifier
+}
+
+func (tr *TableReference) generateCode(g *generator) string {
+	s := tr.Name.generateCode(g)
+	if tr.Alias != nil {
+		s = fmt.Sprintf("%s %s %s", s, g.keyword("AS"), tr.Alias.generateCode(g))
+	}
+
+	return s
+}
+
+// LimitClause represents LIMIT and OFFSET, which are also parsed from the
+// standard OFFSET n ROWS FETCH FIRST n ROWS ONLY form
+type LimitClause struct {
+	span
+	Count  Expression
+	Offset Expression
+	// Comma is set when written as LIMIT offset, count
+	Comma bool
+}
+
+func (lc *LimitClause) generateCode(g *generator) string {
+	var count, offset string
+	if lc.Count != nil {
+		count = lc.Count.generateCode(g)
+	}
+
+	if lc.Offset != nil {
+		offset = lc.Offset.generateCode(g)
+	}
+
+	clauses := []string{}
+	switch g.dialect {
+	case MySQLDialect:
+		// an offset needs a count, the largest one stands for all rows
+		if lc.Offset != nil && lc.Count == nil {
+			count = "18446744073709551615"
+		}
+
+		if lc.Offset != nil {
+			return fmt.Sprintf("%s %s, %s", g.keyword("LIMIT"), offset, count)
+		}
+	case ANSIDialect, SQLServerDialect:
+		if lc.Offset != nil {
+			clauses = append(clauses, fmt.Sprintf("%s %s %s", g.keyword("OFFSET"), offset, g.keyword("ROWS")))
+		}
+
+		if lc.Count != nil {
+			clauses = append(clauses, fmt.Sprintf("%s %s %s", g.keyword("FETCH FIRST"), count, g.keyword("ROWS ONLY")))
+		}
+
+		return strings.Join(clauses, g.newline())
+	case SQLiteDialect:
+		if lc.Count == nil {
+			count = "-1"
+		}
+	}
+
+	if count != "" {
+		clauses = append(clauses, g.keyword("LIMIT")+" "+count)
+	}
+
+	if lc.Offset != nil {
+		clauses = append(clauses, g.keyword("OFFSET")+" "+offset)
+	}
+
+	return strings.Join(clauses, g.newline())
 }
 
 // SelectStatement represents a select statement
 type SelectStatement struct {
-	item  *[]*selectItem
-	from  *token
-	where *expression
+	span
+	Items []*SelectItem
+	From  *TableReference
+	Where Expression
+	Limit *LimitClause
 }
 
 // GenerateCode for literals in select statements based on the type
 func (ss SelectStatement) GenerateCode() string {
+	return ss.generateCode(&generator{}) + ";"
+}
+
+func (ss SelectStatement) generateCode(g *generator) string {
 	item := []string{}
-	for _, i := range *ss.item {
-		s := "\t*"
-		if !i.asterisk {
-			s = "\t" + i.exp.generateCode()
+	for _, i := range ss.Items {
+		item = append(item, i.generateCode(g))
+	}
 
-			if i.as != nil {
-				s = fmt.Sprintf("\t%s AS \"%s\"", s, i.as.value)
-			}
+	// SQL Server takes a count as TOP, but an offset only after ORDER BY
+	top := ""
+	limit := ""
+	if ss.Limit != nil && g.dialect == SQLServerDialect && ss.Limit.Offset == nil {
+		top = fmt.Sprintf(" %s (%s)", g.keyword("TOP"), ss.Limit.Count.generateCode(g))
+	} else if ss.Limit != nil {
+		if g.dialect == SQLServerDialect {
+			g.unsupported("OFFSET without ORDER BY")
 		}
-		item = append(item, s)
+
+		limit = g.newline() + ss.Limit.generateCode(g)
 	}
 
 	from := ""
-	if ss.from != nil {
-		from = fmt.Sprintf("\nFROM\n\t\"%s\"", ss.from.value)
+	if ss.From != nil {
+		from = g.newline() + g.keyword("FROM") + g.newline() + g.indented([]string{ss.From.generateCode(g)})
 	}
 
 	where := ""
-	if ss.where != nil {
-		where = fmt.Sprintf("\nWHERE\n\t%s", ss.where.generateCode())
+	if ss.Where != nil {
+		where = g.newline() + g.keyword("WHERE") + g.newline() + g.indented([]string{ss.Where.generateCode(g)})
 	}
 
-	return fmt.Sprintf("SELECT\n%s%s%s;", strings.Join(item, ",\n"), from, where)
+	return g.keyword("SELECT") + top + g.newline() + g.indented(item) + from + where + limit
 }
 
-type columnDefinition struct {
-	name       token
-	datatype   token
-	primaryKey bool
+// ColumnDefinition is a column of a create table statement
+type ColumnDefinition struct {
+	span
+	Name *Identifier
+	// DataType is the type keyword in uppercase, e.g. INT
+	DataType      string
+	PrimaryKey    bool
+	AutoIncrement bool
+}
+
+func (cd *ColumnDefinition) generateCode(g *generator) string {
+	datatype := cd.DataType
+	if g.dialect == SQLServerDialect {
+		switch keyword(strings.ToLower(cd.DataType)) {
+		case textKeyword:
+			datatype = "NVARCHAR(MAX)"
+		case boolKeyword:
+			datatype = "BIT"
+		}
+	}
+
+	autoIncrement := ""
+	if cd.AutoIncrement {
+		switch g.dialect {
+		case MySQLDialect:
+			autoIncrement = " " + g.keyword("AUTO_INCREMENT")
+		case SQLServerDialect:
+			autoIncrement = " " + g.keyword("IDENTITY") + "(1, 1)"
+		case SQLiteDialect:
+			// SQLite only auto increments integer primary keys
+			if !cd.PrimaryKey {
+				g.unsupported("AUTOINCREMENT outside of the primary key")
+			}
+
+			datatype = "INTEGER"
+		default:
+			autoIncrement = " " + g.keyword("GENERATED BY DEFAULT AS IDENTITY")
+		}
+	}
+
+	modifiers := autoIncrement
+	if cd.PrimaryKey {
+		modifiers += " " + g.keyword("PRIMARY KEY")
+	}
+
+	if cd.AutoIncrement && g.dialect == SQLiteDialect {
+		modifiers += " " + g.keyword("AUTOINCREMENT")
+	}
+
+	return fmt.Sprintf("%s %s%s", cd.Name.generateCode(g), g.keyword(datatype), modifiers)
 }
 
 // CreateTableStatement represents a create table statement
 type CreateTableStatement struct {
-	name token
-	cols *[]*columnDefinition
+	span
+	Name    *QualifiedName
+	Columns []*ColumnDefinition
 }
 
 // GenerateCode for create table statements based on table definitions
 func (cts CreateTableStatement) GenerateCode() string {
+	return cts.generateCode(&generator{})
+}
+
+func (cts CreateTableStatement) generateCode(g *generator) string {
 	cols := []string{}
-	for _, col := range *cts.cols {
-		modifiers := ""
-		if col.primaryKey {
-			modifiers += " " + "PRIMARY KEY"
-		}
-		spec := fmt.Sprintf("\t\"%s\" %s%s", col.name.value, strings.ToUpper(col.datatype.value), modifiers)
-		cols = append(cols, spec)
+	for _, col := range cts.Columns {
+		cols = append(cols, col.generateCode(g))
+	}
+
+	if g.printer.Compact {
+		return fmt.Sprintf("%s %s (%s);", g.keyword("CREATE TABLE"), cts.Name.generateCode(g), g.list(cols))
+	}
+
+	return fmt.Sprintf("%s %s (\n%s\n);", g.keyword("CREATE TABLE"), cts.Name.generateCode(g), g.indented(cols))
+}
+
+// IndexElement is an indexed column or expression
+type IndexElement struct {
+	span
+	Expression Expression
+	// Order is ASC, DESC or empty
+	Order string
+	// Nulls is FIRST, LAST or empty
+	Nulls string
+}
+
+func (ie *IndexElement) generateCode(g *generator) string {
+	s := ie.Expression.generateCode(g)
+	if _, ok := ie.Expression.(*BinaryExpression); ok && g.printer.MinimalParentheses {
+		// expressions are only indexed within parentheses
+		s = "(" + s + ")"
+	}
+
+	if ie.Order != "" {
+		s += " " + g.keyword(ie.Order)
+	}
+
+	if ie.Nulls != "" {
+		g.require("NULLS "+ie.Nulls, PostgreSQLDialect, SQLiteDialect, ANSIDialect)
+		s += " " + g.keyword("NULLS "+ie.Nulls)
 	}
 
-	return fmt.Sprintf("CREATE TABLE \"%s\" (\n%s\n);", cts.name.value, strings.Join(cols, ",\n"))
+	return s
 }
 
 // CreateIndexStatement represents a create index statement
 type CreateIndexStatement struct {
-	name       token
-	unique     bool
-	primaryKey bool
-	table      token
-	exp        expression
+	span
+	Name       *Identifier
+	Unique     bool
+	PrimaryKey bool
+	Table      *QualifiedName
+	// Method is the index method of USING, e.g. btree
+	Method   *Identifier
+	Elements []*IndexElement
+	Include  []*Identifier
+	Where    Expression
 }
 
 // GenerateCode for create index statements
 func (cis CreateIndexStatement) GenerateCode() string {
+	return cis.generateCode(&generator{})
+}
+
+func (cis CreateIndexStatement) generateCode(g *generator) string {
 	unique := ""
-	if cis.unique {
-		unique = " UNIQUE"
+	if cis.Unique {
+		unique = " " + g.keyword("UNIQUE")
+	}
+
+	method := ""
+	if cis.Method != nil {
+		g.require("USING "+cis.Method.Name, PostgreSQLDialect)
+		method = " " + g.keyword("USING") + " " + cis.Method.generateCode(g)
+	}
+
+	elements := []string{}
+	for _, e := range cis.Elements {
+		elements = append(elements, e.generateCode(g))
+	}
+
+	include := ""
+	if cis.Include != nil {
+		g.require("INCLUDE", PostgreSQLDialect, SQLServerDialect)
+		include = fmt.Sprintf("%s%s (%s)", g.space(), g.keyword("INCLUDE"), g.generateIdentifierList(cis.Include))
+	}
+
+	where := ""
+	if cis.Where != nil {
+		g.require("partial index", PostgreSQLDialect, SQLiteDialect, SQLServerDialect)
+		where = g.space() + g.keyword("WHERE") + " " + cis.Where.generateCode(g)
 	}
-	return fmt.Sprintf("CREATE%s INDEX \"%s\" ON \"%s\" (%s);", unique, cis.name.value, cis.table.value, cis.exp.generateCode())
+
+	return fmt.Sprintf("%s%s %s %s %s %s%s (%s)%s%s;", g.keyword("CREATE"), unique, g.keyword("INDEX"), cis.Name.generateCode(g), g.keyword("ON"), cis.Table.generateCode(g), method, g.list(elements), include, where)
+}
+
+// DropIndexStatement represents an index delete statement
+type DropIndexStatement struct {
+	span
+	Name *QualifiedName
+}
+
+// GenerateCode for drop index statements
+func (dis DropIndexStatement) GenerateCode() string {
+	return dis.generateCode(&generator{})
+}
+
+func (dis DropIndexStatement) generateCode(g *generator) string {
+	// the others need the table of the index as well
+	g.require("DROP INDEX without table", PostgreSQLDialect, SQLiteDialect, ANSIDialect)
+	return fmt.Sprintf("%s %s;", g.keyword("DROP INDEX"), dis.Name.generateCode(g))
 }
 
 // DropTableStatement represents a table delete statement
 type DropTableStatement struct {
-	name token
+	span
+	Name *QualifiedName
 }
 
 // GenerateCode for drop table statements
 func (dts DropTableStatement) GenerateCode() string {
-	return fmt.Sprintf("DROP TABLE \"%s\";", dts.name.value)
+	return dts.generateCode(&generator{})
+}
+
+func (dts DropTableStatement) generateCode(g *generator) string {
+	return fmt.Sprintf("%s %s;", g.keyword("DROP TABLE"), dts.Name.generateCode(g))
+}
+
+// CreateViewStatement represents a create view or create materialized view
+// statement
+type CreateViewStatement struct {
+	span
+	OrReplace    bool
+	Materialized bool
+	Name         *QualifiedName
+	Columns      []*Identifier
+	Query        *SelectStatement
+}
+
+// GenerateCode for create view statements
+func (cvs CreateViewStatement) GenerateCode() string {
+	return cvs.generateCode(&generator{})
+}
+
+func (cvs CreateViewStatement) generateCode(g *generator) string {
+	orReplace := ""
+	if cvs.OrReplace && g.dialect == SQLServerDialect {
+		orReplace = " " + g.keyword("OR ALTER")
+	} else if cvs.OrReplace {
+		g.require("CREATE OR REPLACE VIEW", PostgreSQLDialect, MySQLDialect)
+		orReplace = " " + g.keyword("OR REPLACE")
+	}
+
+	materialized := ""
+	if cvs.Materialized {
+		g.require("MATERIALIZED VIEW", PostgreSQLDialect)
+		materialized = " " + g.keyword("MATERIALIZED")
+	}
+
+	cols := ""
+	if cvs.Columns != nil {
+		cols = fmt.Sprintf(" (%s)", g.generateIdentifierList(cvs.Columns))
+	}
+
+	return fmt.Sprintf("%s%s%s %s %s%s %s%s%s;", g.keyword("CREATE"), orReplace, materialized, g.keyword("VIEW"), cvs.Name.generateCode(g), cols, g.keyword("AS"), g.newline(), cvs.Query.generateCode(g))
+}
+
+// RefreshMaterializedViewStatement represents a materialized view refresh
+type RefreshMaterializedViewStatement struct {
+	span
+	Name *QualifiedName
+}
+
+// GenerateCode for refresh materialized view statements
+func (rmvs RefreshMaterializedViewStatement) GenerateCode() string {
+	return rmvs.generateCode(&generator{})
+}
+
+func (rmvs RefreshMaterializedViewStatement) generateCode(g *generator) string {
+	g.require("REFRESH MATERIALIZED VIEW", PostgreSQLDialect)
+	return fmt.Sprintf("%s %s;", g.keyword("REFRESH MATERIALIZED VIEW"), rmvs.Name.generateCode(g))
+}
+
+// DropViewStatement represents a view or materialized view delete statement
+type DropViewStatement struct {
+	span
+	Materialized bool
+	Name         *QualifiedName
+}
+
+// GenerateCode for drop view statements
+func (dvs DropViewStatement) GenerateCode() string {
+	return dvs.generateCode(&generator{})
+}
+
+func (dvs DropViewStatement) generateCode(g *generator) string {
+	materialized := ""
+	if dvs.Materialized {
+		g.require("MATERIALIZED VIEW", PostgreSQLDialect)
+		materialized = g.keyword("MATERIALIZED") + " "
+	}
+
+	return fmt.Sprintf("%s %s%s %s;", g.keyword("DROP"), materialized, g.keyword("VIEW"), dvs.Name.generateCode(g))
+}
+
+// SetClause is a column = value assignment
+type SetClause struct {
+	span
+	Column *Identifier
+	Value  Expression
+}
+
+func (g *generator) generateSetClauses(set []*SetClause) string {
+	clauses := []string{}
+	for _, sc := range set {
+		clauses = append(clauses, fmt.Sprintf("%s = %s", sc.Column.generateCode(g), sc.Value.generateCode(g)))
+	}
+
+	return g.list(clauses)
+}
+
+// OnConflictClause is the ON CONFLICT clause of an insert statement
+type OnConflictClause struct {
+	span
+	Columns    []*Identifier
+	Constraint *Identifier
+	DoNothing  bool
+	Set        []*SetClause
+	Where      Expression
+}
+
+func (occ *OnConflictClause) generateCode(g *generator) string {
+	g.require("ON CONFLICT", PostgreSQLDialect, SQLiteDialect)
+
+	target := ""
+	if occ.Columns != nil {
+		target = fmt.Sprintf(" (%s)", g.generateIdentifierList(occ.Columns))
+	} else if occ.Constraint != nil {
+		g.require("ON CONFLICT ON CONSTRAINT", PostgreSQLDialect)
+		target = fmt.Sprintf(" %s %s", g.keyword("ON CONSTRAINT"), occ.Constraint.generateCode(g))
+	}
+
+	if occ.DoNothing {
+		return fmt.Sprintf("%s%s %s", g.keyword("ON CONFLICT"), target, g.keyword("DO NOTHING"))
+	}
+
+	where := ""
+	if occ.Where != nil {
+		where = g.space() + g.keyword("WHERE") + " " + occ.Where.generateCode(g)
+	}
+
+	return fmt.Sprintf("%s%s %s %s%s", g.keyword("ON CONFLICT"), target, g.keyword("DO UPDATE SET"), g.generateSetClauses(occ.Set), where)
 }
 
 // InsertStatement represents insert queries
 type InsertStatement struct {
-	table  token
-	values *[]*expression
+	span
+	Table   *QualifiedName
+	Columns []*Identifier
+	// the rows are taken from one of Values, DefaultValues and Query
+	Values        [][]Expression
+	DefaultValues bool
+	Query         *SelectStatement
+	OnConflict    *OnConflictClause
+	// OnDuplicateKey holds MySQL's ON DUPLICATE KEY UPDATE assignments
+	OnDuplicateKey []*SetClause
+	Returning      []*SelectItem
 }
 
 // GenerateCode for insert statements
 func (is InsertStatement) GenerateCode() string {
-	values := []string{}
-	for _, exp := range *is.values {
-		values = append(values, exp.generateCode())
+	return is.generateCode(&generator{})
+}
+
+func (is InsertStatement) generateCode(g *generator) string {
+	cols := ""
+	if is.Columns != nil {
+		cols = fmt.Sprintf(" (%s)", g.generateIdentifierList(is.Columns))
+	}
+
+	source := g.space() + g.keyword("DEFAULT VALUES")
+	if is.Query != nil {
+		source = g.newline() + is.Query.generateCode(g)
+	} else if is.Values != nil {
+		rows := []string{}
+		for _, row := range is.Values {
+			values := []string{}
+			for _, exp := range row {
+				values = append(values, exp.generateCode(g))
+			}
+			rows = append(rows, fmt.Sprintf("(%s)", g.list(values)))
+		}
+		source = g.space() + g.keyword("VALUES") + " " + g.list(rows)
+	} else if g.dialect == MySQLDialect {
+		source = " () " + g.keyword("VALUES") + " ()"
+	}
+
+	upsert := ""
+	if is.OnConflict != nil {
+		upsert = g.space() + is.OnConflict.generateCode(g)
+	} else if is.OnDuplicateKey != nil {
+		g.require("ON DUPLICATE KEY UPDATE", MySQLDialect)
+		upsert = g.space() + g.keyword("ON DUPLICATE KEY UPDATE") + " " + g.generateSetClauses(is.OnDuplicateKey)
+	}
+
+	return fmt.Sprintf("%s %s%s%s%s%s;", g.keyword("INSERT INTO"), is.Table.generateCode(g), cols, source, upsert, g.generateReturning(is.Returning))
+}
+
+// UpdateStatement represents update queries
+type UpdateStatement struct {
+	span
+	Table     *QualifiedName
+	Set       []*SetClause
+	Where     Expression
+	Returning []*SelectItem
+}
+
+// GenerateCode for update statements
+func (us UpdateStatement) GenerateCode() string {
+	return us.generateCode(&generator{})
+}
+
+func (us UpdateStatement) generateCode(g *generator) string {
+	where := ""
+	if us.Where != nil {
+		where = g.space() + g.keyword("WHERE") + " " + us.Where.generateCode(g)
+	}
+
+	return fmt.Sprintf("%s %s%s%s %s%s%s;", g.keyword("UPDATE"), us.Table.generateCode(g), g.space(), g.keyword("SET"), g.generateSetClauses(us.Set), where, g.generateReturning(us.Returning))
+}
+
+// DeleteStatement represents delete queries
+type DeleteStatement struct {
+	span
+	Table     *QualifiedName
+	Where     Expression
+	Returning []*SelectItem
+}
+
+// GenerateCode for delete statements
+func (ds DeleteStatement) GenerateCode() string {
+	return ds.generateCode(&generator{})
+}
+
+func (ds DeleteStatement) generateCode(g *generator) string {
+	where := ""
+	if ds.Where != nil {
+		where = g.space() + g.keyword("WHERE") + " " + ds.Where.generateCode(g)
 	}
-	return fmt.Sprintf("INSERT INTO \"%s\" VALUES (%s);", is.table.value, strings.Join(values, ", "))
+
+	return fmt.Sprintf("%s %s%s%s;", g.keyword("DELETE FROM"), ds.Table.generateCode(g), where, g.generateReturning(ds.Returning))
+}
+
+// BeginStatement represents BEGIN and START TRANSACTION statements
+type BeginStatement struct {
+	span
+	// Start is set for START TRANSACTION rather than BEGIN
+	Start       bool
+	Transaction bool
+	// IsolationLevel is e.g. SERIALIZABLE or READ COMMITTED, or empty
+	IsolationLevel string
+	// AccessMode is READ ONLY, READ WRITE or empty
+	AccessMode string
+}
+
+// GenerateCode for begin statements
+func (bs BeginStatement) GenerateCode() string {
+	return bs.generateCode(&generator{})
+}
+
+func (bs BeginStatement) generateCode(g *generator) string {
+	s := "BEGIN"
+	switch {
+	case g.dialect == MySQLDialect || g.dialect == ANSIDialect:
+		s = "START TRANSACTION"
+	case g.dialect == SQLiteDialect || g.dialect == SQLServerDialect:
+		s = "BEGIN TRANSACTION"
+	case bs.Start:
+		s = "START TRANSACTION"
+	case bs.Transaction:
+		s += " TRANSACTION"
+	}
+
+	if bs.IsolationLevel != "" {
+		g.require("ISOLATION LEVEL", PostgreSQLDialect, ANSIDialect)
+		s += " ISOLATION LEVEL " + bs.IsolationLevel
+	}
+
+	if bs.AccessMode != "" {
+		g.require(bs.AccessMode, PostgreSQLDialect, MySQLDialect, ANSIDialect)
+		s += " " + bs.AccessMode
+	}
+
+	return g.keyword(s) + ";"
+}
+
+// CommitStatement represents a transaction commit
+type CommitStatement struct {
+	span
+	Transaction bool
+}
+
+// GenerateCode for commit statements
+func (cs CommitStatement) GenerateCode() string {
+	return cs.generateCode(&generator{})
+}
+
+func (cs CommitStatement) generateCode(g *generator) string {
+	switch g.dialect {
+	case SQLServerDialect:
+		return g.keyword("COMMIT TRANSACTION") + ";"
+	case MySQLDialect, ANSIDialect:
+		return g.keyword("COMMIT") + ";"
+	}
+
+	if cs.Transaction {
+		return g.keyword("COMMIT TRANSACTION") + ";"
+	}
+
+	return g.keyword("COMMIT") + ";"
+}
+
+// RollbackStatement represents a transaction or savepoint rollback
+type RollbackStatement struct {
+	span
+	Transaction bool
+	Savepoint   *Identifier
+}
+
+// GenerateCode for rollback statements
+func (rs RollbackStatement) GenerateCode() string {
+	return rs.generateCode(&generator{})
+}
+
+func (rs RollbackStatement) generateCode(g *generator) string {
+	if g.dialect == SQLServerDialect {
+		if rs.Savepoint != nil {
+			return fmt.Sprintf("%s %s;", g.keyword("ROLLBACK TRANSACTION"), rs.Savepoint.generateCode(g))
+		}
+
+		return g.keyword("ROLLBACK TRANSACTION") + ";"
+	}
+
+	s := "ROLLBACK"
+	if rs.Transaction && g.dialect != MySQLDialect && g.dialect != ANSIDialect {
+		s += " TRANSACTION"
+	}
+
+	if rs.Savepoint != nil {
+		s += " TO SAVEPOINT"
+		return fmt.Sprintf("%s %s;", g.keyword(s), rs.Savepoint.generateCode(g))
+	}
+
+	return g.keyword(s) + ";"
+}
+
+// SavepointStatement represents a savepoint definition
+type SavepointStatement struct {
+	span
+	Name *Identifier
+}
+
+// GenerateCode for savepoint statements
+func (ss SavepointStatement) GenerateCode() string {
+	return ss.generateCode(&generator{})
+}
+
+func (ss SavepointStatement) generateCode(g *generator) string {
+	if g.dialect == SQLServerDialect {
+		return fmt.Sprintf("%s %s;", g.keyword("SAVE TRANSACTION"), ss.Name.generateCode(g))
+	}
+
+	return fmt.Sprintf("%s %s;", g.keyword("SAVEPOINT"), ss.Name.generateCode(g))
+}
+
+// ReleaseSavepointStatement represents a savepoint release
+type ReleaseSavepointStatement struct {
+	span
+	Name *Identifier
+}
+
+// GenerateCode for release savepoint statements
+func (rss ReleaseSavepointStatement) GenerateCode() string {
+	return rss.generateCode(&generator{})
+}
+
+func (rss ReleaseSavepointStatement) generateCode(g *generator) string {
+	g.require("RELEASE SAVEPOINT", PostgreSQLDialect, MySQLDialect, SQLiteDialect, ANSIDialect)
+	return fmt.Sprintf("%s %s;", g.keyword("RELEASE SAVEPOINT"), rss.Name.generateCode(g))
 }
 
 // AstKind representation
@@ -172,31 +1001,163 @@ const (
 	DropTableKind
 	// InsertKind representation
 	InsertKind
+	// DropIndexKind representation
+	DropIndexKind
+	// CreateViewKind representation
+	CreateViewKind
+	// RefreshMaterializedViewKind representation
+	RefreshMaterializedViewKind
+	// DropViewKind representation
+	DropViewKind
+	// UpdateKind representation
+	UpdateKind
+	// DeleteKind representation
+	DeleteKind
+	// BeginKind representation
+	BeginKind
+	// CommitKind representation
+	CommitKind
+	// RollbackKind representation
+	RollbackKind
+	// SavepointKind representation
+	SavepointKind
+	// ReleaseSavepointKind representation
+	ReleaseSavepointKind
 )
 
 // Statement represents a SQL statement
 type Statement struct {
-	SelectStatement      *SelectStatement
-	CreateTableStatement *CreateTableStatement
-	CreateIndexStatement *CreateIndexStatement
-	DropTableStatement   *DropTableStatement
-	InsertStatement      *InsertStatement
-	Kind                 AstKind
+	SelectStatement                  *SelectStatement
+	CreateTableStatement             *CreateTableStatement
+	CreateIndexStatement             *CreateIndexStatement
+	DropTableStatement               *DropTableStatement
+	InsertStatement                  *InsertStatement
+	DropIndexStatement               *DropIndexStatement
+	CreateViewStatement              *CreateViewStatement
+	RefreshMaterializedViewStatement *RefreshMaterializedViewStatement
+	DropViewStatement                *DropViewStatement
+	UpdateStatement                  *UpdateStatement
+	DeleteStatement                  *DeleteStatement
+	BeginStatement                   *BeginStatement
+	CommitStatement                  *CommitStatement
+	RollbackStatement                *RollbackStatement
+	SavepointStatement               *SavepointStatement
+	ReleaseSavepointStatement        *ReleaseSavepointStatement
+	Kind                             AstKind
+	// LeadingComments are written before the statement and TrailingComments
+	// after it on the same line. They are only kept when parsing with
+	// KeepComments and only printed by a Printer.
+	LeadingComments  []string
+	TrailingComments []string
+	// tokens of the statement and its semicolons when parsing with
+	// KeepComments, for the Printer to put the comments within the
+	// statement back next to their tokens
+	tokens []*token
+}
+
+// Pos of the first character of the statement
+func (s Statement) Pos() Pos {
+	return s.node().Pos()
+}
+
+// End is the position right after the statement, before any semicolon
+func (s Statement) End() Pos {
+	return s.node().End()
+}
+
+// node is the statement of the given Kind
+func (s Statement) node() Node {
+	switch s.Kind {
+	case SelectKind:
+		return s.SelectStatement
+	case CreateTableKind:
+		return s.CreateTableStatement
+	case CreateIndexKind:
+		return s.CreateIndexStatement
+	case DropTableKind:
+		return s.DropTableStatement
+	case InsertKind:
+		return s.InsertStatement
+	case DropIndexKind:
+		return s.DropIndexStatement
+	case CreateViewKind:
+		return s.CreateViewStatement
+	case RefreshMaterializedViewKind:
+		return s.RefreshMaterializedViewStatement
+	case DropViewKind:
+		return s.DropViewStatement
+	case UpdateKind:
+		return s.UpdateStatement
+	case DeleteKind:
+		return s.DeleteStatement
+	case BeginKind:
+		return s.BeginStatement
+	case CommitKind:
+		return s.CommitStatement
+	case RollbackKind:
+		return s.RollbackStatement
+	case SavepointKind:
+		return s.SavepointStatement
+	case ReleaseSavepointKind:
+		return s.ReleaseSavepointStatement
+	}
+
+	return span{}
 }
 
 // GenerateCode based on the statement type
 func (s Statement) GenerateCode() string {
+	return s.generateCode(&generator{})
+}
+
+// GenerateCodeFor renders the statement for the given dialect, failing on
+// the first construct the dialect can't express
+func (s Statement) GenerateCodeFor(d Dialect) (string, error) {
+	g := &generator{dialect: d}
+	code := s.generateCode(g)
+	if g.err != nil {
+		return "", g.err
+	}
+
+	return code, nil
+}
+
+func (s Statement) generateCode(g *generator) string {
+	g.numberParameters(&s)
+
 	switch s.Kind {
 	case SelectKind:
-		return s.SelectStatement.GenerateCode()
+		return s.SelectStatement.generateCode(g) + ";"
 	case CreateTableKind:
-		return s.CreateTableStatement.GenerateCode()
+		return s.CreateTableStatement.generateCode(g)
 	case CreateIndexKind:
-		return s.CreateIndexStatement.GenerateCode()
+		return s.CreateIndexStatement.generateCode(g)
 	case DropTableKind:
-		return s.DropTableStatement.GenerateCode()
+		return s.DropTableStatement.generateCode(g)
 	case InsertKind:
-		return s.InsertStatement.GenerateCode()
+		return s.InsertStatement.generateCode(g)
+	case DropIndexKind:
+		return s.DropIndexStatement.generateCode(g)
+	case CreateViewKind:
+		return s.CreateViewStatement.generateCode(g)
+	case RefreshMaterializedViewKind:
+		return s.RefreshMaterializedViewStatement.generateCode(g)
+	case DropViewKind:
+		return s.DropViewStatement.generateCode(g)
+	case UpdateKind:
+		return s.UpdateStatement.generateCode(g)
+	case DeleteKind:
+		return s.DeleteStatement.generateCode(g)
+	case BeginKind:
+		return s.BeginStatement.generateCode(g)
+	case CommitKind:
+		return s.CommitStatement.generateCode(g)
+	case RollbackKind:
+		return s.RollbackStatement.generateCode(g)
+	case SavepointKind:
+		return s.SavepointStatement.generateCode(g)
+	case ReleaseSavepointKind:
+		return s.ReleaseSavepointStatement.generateCode(g)
 	}
 
 	return "?unknown?"
@@ -206,3 +1167,37 @@ func (s Statement) GenerateCode() string {
 type Ast struct {
 	Statements []*Statement
 }
+
+// Pos of the first statement
+func (a *Ast) Pos() Pos {
+	if len(a.Statements) == 0 {
+		return Pos{}
+	}
+
+	return a.Statements[0].Pos()
+}
+
+// End of the last statement
+func (a *Ast) End() Pos {
+	if len(a.Statements) == 0 {
+		return Pos{}
+	}
+
+	return a.Statements[len(a.Statements)-1].End()
+}
+
+// GenerateCodeFor renders every statement for the given dialect, one per
+// line
+func (a *Ast) GenerateCodeFor(d Dialect) (string, error) {
+	stmts := []string{}
+	for _, stmt := range a.Statements {
+		code, err := stmt.GenerateCodeFor(d)
+		if err != nil {
+			return "", err
+		}
+
+		stmts = append(stmts, code)
+	}
+
+	return strings.Join(stmts, "\n"), nil
+}
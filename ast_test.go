@@ -1,42 +1,60 @@
 package gosqlshell
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func TestStatement_GenerateCode(t *testing.T) {
+	id := func(name string) *Identifier {
+		return &Identifier{Name: name}
+	}
+
+	qualified := func(parts ...string) *QualifiedName {
+		qn := &QualifiedName{}
+		for _, part := range parts {
+			qn.Parts = append(qn.Parts, id(part))
+		}
+
+		return qn
+	}
+
+	literal := func(kind LiteralKind, value string) *Literal {
+		return &Literal{Kind: kind, Value: value}
+	}
+
 	tests := []struct {
 		result string
 		stmt   Statement
 	}{
 		{
-			`DROP TABLE "foo";`,
+			`DROP TABLE foo;`,
 			Statement{
 				DropTableStatement: &DropTableStatement{
-					name: token{value: "foo"},
+					Name: qualified("foo"),
 				},
 				Kind: DropTableKind,
 			},
 		},
 		{
-			`CREATE TABLE "users" (
-	"id" INT PRIMARY KEY,
-	"name" TEXT
+			`CREATE TABLE users (
+	id INT PRIMARY KEY,
+	name TEXT
 );`,
 			Statement{
 				CreateTableStatement: &CreateTableStatement{
-					name: token{value: "users"},
-					cols: &[]*columnDefinition{
+					Name: qualified("users"),
+					Columns: []*ColumnDefinition{
 						{
-							name:       token{value: "id"},
-							datatype:   token{value: "int"},
-							primaryKey: true,
+							Name:       id("id"),
+							DataType:   "INT",
+							PrimaryKey: true,
 						},
 						{
-							name:     token{value: "name"},
-							datatype: token{value: "text"},
+							Name:     id("name"),
+							DataType: "TEXT",
 						},
 					},
 				},
@@ -44,61 +62,580 @@ func TestStatement_GenerateCode(t *testing.T) {
 			},
 		},
 		{
-			`CREATE UNIQUE INDEX "age_idx" ON "users" ("age");`,
+			`CREATE UNIQUE INDEX age_idx ON users (age);`,
 			Statement{
 				CreateIndexStatement: &CreateIndexStatement{
-					name:   token{value: "age_idx"},
-					unique: true,
-					table:  token{value: "users"},
-					exp:    expression{literal: &token{value: "age", kind: identifierKind}, kind: literalKind},
+					Name:   id("age_idx"),
+					Unique: true,
+					Table:  qualified("users"),
+					Elements: []*IndexElement{
+						{Expression: id("age")},
+					},
 				},
 				Kind: CreateIndexKind,
 			},
 		},
 		{
-			`INSERT INTO "foo" VALUES (1, 'flubberty', true);`,
+			`CREATE INDEX name_idx ON users USING btree (last_name, age DESC NULLS LAST) INCLUDE (id) WHERE (active = true);`,
+			Statement{
+				CreateIndexStatement: &CreateIndexStatement{
+					Name:   id("name_idx"),
+					Table:  qualified("users"),
+					Method: id("btree"),
+					Elements: []*IndexElement{
+						{Expression: id("last_name")},
+						{
+							Expression: id("age"),
+							Order:      "DESC",
+							Nulls:      "LAST",
+						},
+					},
+					Include: []*Identifier{id("id")},
+					Where: &BinaryExpression{
+						Left:     id("active"),
+						Right:    literal(BoolLiteral, "true"),
+						Operator: "=",
+					},
+				},
+				Kind: CreateIndexKind,
+			},
+		},
+		{
+			`CREATE INDEX i ON users USING "gist (x); DROP TABLE users; --" (location);`,
+			Statement{
+				CreateIndexStatement: &CreateIndexStatement{
+					Name:   id("i"),
+					Table:  qualified("users"),
+					Method: id("gist (x); DROP TABLE users; --"),
+					Elements: []*IndexElement{
+						{Expression: id("location")},
+					},
+				},
+				Kind: CreateIndexKind,
+			},
+		},
+		{
+			`DROP INDEX age_idx;`,
+			Statement{
+				DropIndexStatement: &DropIndexStatement{
+					Name: qualified("age_idx"),
+				},
+				Kind: DropIndexKind,
+			},
+		},
+		{
+			`INSERT INTO foo VALUES (1, 'flubberty', true);`,
+			Statement{
+				InsertStatement: &InsertStatement{
+					Table: qualified("foo"),
+					Values: [][]Expression{
+						{
+							literal(NumericLiteral, "1"),
+							literal(StringLiteral, "flubberty"),
+							literal(BoolLiteral, "true"),
+						},
+					},
+				},
+				Kind: InsertKind,
+			},
+		},
+		{
+			`INSERT INTO foo (id, name) VALUES (1, DEFAULT), (2, 'bar');`,
+			Statement{
+				InsertStatement: &InsertStatement{
+					Table:   qualified("foo"),
+					Columns: []*Identifier{id("id"), id("name")},
+					Values: [][]Expression{
+						{
+							literal(NumericLiteral, "1"),
+							literal(DefaultLiteral, "DEFAULT"),
+						},
+						{
+							literal(NumericLiteral, "2"),
+							literal(StringLiteral, "bar"),
+						},
+					},
+				},
+				Kind: InsertKind,
+			},
+		},
+		{
+			`INSERT INTO foo (id) VALUES (1) ON CONFLICT (id) DO UPDATE SET id = excluded.id;`,
 			Statement{
 				InsertStatement: &InsertStatement{
-					table: token{value: "foo"},
-					values: &[]*expression{
-						{literal: &token{value: "1", kind: numericKind}, kind: literalKind},
-						{literal: &token{value: "flubberty", kind: stringKind}, kind: literalKind},
-						{literal: &token{value: "true", kind: boolKind}, kind: literalKind},
+					Table:   qualified("foo"),
+					Columns: []*Identifier{id("id")},
+					Values: [][]Expression{
+						{literal(NumericLiteral, "1")},
+					},
+					OnConflict: &OnConflictClause{
+						Columns: []*Identifier{id("id")},
+						Set: []*SetClause{
+							{Column: id("id"), Value: qualified("excluded", "id")},
+						},
+					},
+				},
+				Kind: InsertKind,
+			},
+		},
+		{
+			`UPDATE foo SET name = 'bar' RETURNING id AS foo_id;`,
+			Statement{
+				UpdateStatement: &UpdateStatement{
+					Table: qualified("foo"),
+					Set: []*SetClause{
+						{Column: id("name"), Value: literal(StringLiteral, "bar")},
+					},
+					Returning: []*SelectItem{
+						{Expression: id("id"), Alias: id("foo_id")},
 					},
 				},
+				Kind: UpdateKind,
+			},
+		},
+		{
+			`DELETE FROM foo RETURNING *;`,
+			Statement{
+				DeleteStatement: &DeleteStatement{
+					Table:     qualified("foo"),
+					Returning: []*SelectItem{{Asterisk: true}},
+				},
+				Kind: DeleteKind,
+			},
+		},
+		{
+			`INSERT INTO foo DEFAULT VALUES;`,
+			Statement{
+				InsertStatement: &InsertStatement{
+					Table:         qualified("foo"),
+					DefaultValues: true,
+				},
 				Kind: InsertKind,
 			},
 		},
 		{
 			`SELECT
-	"id",
-	"name"
+	id,
+	name
 FROM
-	"users"
+	users
 WHERE
-	("id" = 2);`,
+	(id = 2);`,
 			Statement{
 				SelectStatement: &SelectStatement{
-					item: &[]*selectItem{
-						{exp: &expression{literal: &token{value: "id", kind: identifierKind}, kind: literalKind}},
-						{exp: &expression{literal: &token{value: "name", kind: identifierKind}, kind: literalKind}},
+					Items: []*SelectItem{
+						{Expression: id("id")},
+						{Expression: id("name")},
 					},
-					from: &token{value: "users"},
-					where: &expression{
-						binary: &binaryExpression{
-							a:  expression{literal: &token{value: "id", kind: identifierKind}, kind: literalKind},
-							b:  expression{literal: &token{value: "2", kind: numericKind}, kind: literalKind},
-							op: token{value: "=", kind: symbolKind},
-						},
-						kind: binaryKind,
+					From: &TableReference{Name: qualified("users")},
+					Where: &BinaryExpression{
+						Left:     id("id"),
+						Right:    literal(NumericLiteral, "2"),
+						Operator: "=",
 					},
 				},
 				Kind: SelectKind,
 			},
 		},
+		{
+			`CREATE OR REPLACE VIEW active_users (id) AS
+SELECT
+	id
+FROM
+	users;`,
+			Statement{
+				CreateViewStatement: &CreateViewStatement{
+					OrReplace: true,
+					Name:      qualified("active_users"),
+					Columns:   []*Identifier{id("id")},
+					Query: &SelectStatement{
+						Items: []*SelectItem{
+							{Expression: id("id")},
+						},
+						From: &TableReference{Name: qualified("users")},
+					},
+				},
+				Kind: CreateViewKind,
+			},
+		},
+		{
+			`REFRESH MATERIALIZED VIEW totals;`,
+			Statement{
+				RefreshMaterializedViewStatement: &RefreshMaterializedViewStatement{
+					Name: qualified("totals"),
+				},
+				Kind: RefreshMaterializedViewKind,
+			},
+		},
+		{
+			`DROP MATERIALIZED VIEW totals;`,
+			Statement{
+				DropViewStatement: &DropViewStatement{
+					Materialized: true,
+					Name:         qualified("totals"),
+				},
+				Kind: DropViewKind,
+			},
+		},
+		{
+			`BEGIN ISOLATION LEVEL SERIALIZABLE READ ONLY;`,
+			Statement{
+				BeginStatement: &BeginStatement{
+					IsolationLevel: "SERIALIZABLE",
+					AccessMode:     "READ ONLY",
+				},
+				Kind: BeginKind,
+			},
+		},
+		{
+			`START TRANSACTION ISOLATION LEVEL READ COMMITTED READ WRITE;`,
+			Statement{
+				BeginStatement: &BeginStatement{
+					Start:          true,
+					IsolationLevel: "READ COMMITTED",
+					AccessMode:     "READ WRITE",
+				},
+				Kind: BeginKind,
+			},
+		},
+		{
+			`ROLLBACK TO SAVEPOINT a;`,
+			Statement{
+				RollbackStatement: &RollbackStatement{
+					Savepoint: id("a"),
+				},
+				Kind: RollbackKind,
+			},
+		},
+		{
+			`RELEASE SAVEPOINT a;`,
+			Statement{
+				ReleaseSavepointStatement: &ReleaseSavepointStatement{
+					Name: id("a"),
+				},
+				Kind: ReleaseSavepointKind,
+			},
+		},
 	}
 
 	for _, test := range tests {
 		assert.Equal(t, test.result, test.stmt.GenerateCode())
 	}
 }
+
+func TestStatement_GenerateCodeFor(t *testing.T) {
+	tests := []struct {
+		source  string
+		dialect Dialect
+		target  Dialect
+		result  string
+		err     bool
+	}{
+		{
+			source: `SELECT "UserId", name || 'x' AS "Label" FROM users WHERE active = true LIMIT 10 OFFSET 20`,
+			target: MySQLDialect,
+			result: "SELECT\n\t`UserId`,\n\tCONCAT(name, 'x') AS `Label`\nFROM\n\tusers\nWHERE\n\t(active = true)\nLIMIT 20, 10;",
+		},
+		{
+			source: `SELECT "UserId" FROM users WHERE active = true LIMIT 10`,
+			target: SQLServerDialect,
+			result: "SELECT TOP (10)\n\t[UserId]\nFROM\n\tusers\nWHERE\n\t(active = 1);",
+		},
+		{
+			source: "SELECT id FROM users OFFSET 20",
+			target: SQLiteDialect,
+			result: "SELECT\n\tid\nFROM\n\tusers\nLIMIT -1\nOFFSET 20;",
+		},
+		{
+			source:  "SELECT id FROM users LIMIT 20, 10",
+			dialect: MySQLDialect,
+			target:  ANSIDialect,
+			result:  "SELECT\n\tid\nFROM\n\tusers\nOFFSET 20 ROWS\nFETCH FIRST 10 ROWS ONLY;",
+		},
+		{
+			source: `SELECT E'a\\b\'c', $$d\e$$, N'f', TIMESTAMP '2024-01-01 10:00:00', INTERVAL '90 minutes'`,
+			target: MySQLDialect,
+			result: "SELECT\n\t'a\\\\b''c',\n\t'd\\\\e',\n\tN'f',\n\tTIMESTAMP '2024-01-01 10:00:00',\n\tINTERVAL 90 MINUTE;",
+		},
+		{
+			source: "SELECT DATE '2024-01-01', X'ff', N'f'",
+			target: SQLServerDialect,
+			result: "SELECT\n\tCAST('2024-01-01' AS DATE),\n\t0xff,\n\tN'f';",
+		},
+		{
+			source: "SELECT DATE '2024-01-01', INTERVAL '2 years'",
+			target: ANSIDialect,
+			result: "SELECT\n\tDATE '2024-01-01',\n\tINTERVAL '2' YEAR;",
+		},
+		{
+			source: "CREATE TABLE users (id SERIAL PRIMARY KEY, name TEXT, active BOOLEAN)",
+			target: SQLServerDialect,
+			result: "CREATE TABLE users (\n\tid INT IDENTITY(1, 1) PRIMARY KEY,\n\tname NVARCHAR(MAX),\n\tactive BIT\n);",
+		},
+		{
+			source:  "CREATE TABLE users (id INT PRIMARY KEY AUTO_INCREMENT)",
+			dialect: MySQLDialect,
+			target:  SQLiteDialect,
+			result:  "CREATE TABLE users (\n\tid INTEGER PRIMARY KEY AUTOINCREMENT\n);",
+		},
+		{
+			source:  "CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT)",
+			dialect: SQLiteDialect,
+			target:  MySQLDialect,
+			result:  "CREATE TABLE users (\n\tid INTEGER AUTO_INCREMENT PRIMARY KEY\n);",
+		},
+		{
+			source: "CREATE TABLE users (id INT GENERATED BY DEFAULT AS IDENTITY)",
+			target: PostgreSQLDialect,
+			result: "CREATE TABLE users (\n\tid INT GENERATED BY DEFAULT AS IDENTITY\n);",
+		},
+		{
+			source: "INSERT INTO users DEFAULT VALUES",
+			target: MySQLDialect,
+			result: "INSERT INTO users () VALUES ();",
+		},
+		{
+			source:  "INSERT INTO users (id, name) VALUES (1, 'ann') ON DUPLICATE KEY UPDATE name = VALUES(name)",
+			dialect: MySQLDialect,
+			target:  MySQLDialect,
+			result:  "INSERT INTO users (id, name) VALUES (1, 'ann') ON DUPLICATE KEY UPDATE name = VALUES(name);",
+		},
+		{
+			source: "CREATE OR REPLACE VIEW v AS SELECT id FROM users",
+			target: SQLServerDialect,
+			result: "CREATE OR ALTER VIEW v AS\nSELECT\n\tid\nFROM\n\tusers;",
+		},
+		{
+			source: "BEGIN TRANSACTION READ ONLY",
+			target: MySQLDialect,
+			result: "START TRANSACTION READ ONLY;",
+		},
+		{
+			source: "ROLLBACK TRANSACTION TO SAVEPOINT a",
+			target: SQLServerDialect,
+			result: "ROLLBACK TRANSACTION a;",
+		},
+		{
+			source: "SAVEPOINT a",
+			target: SQLServerDialect,
+			result: "SAVE TRANSACTION a;",
+		},
+		{
+			source: "START TRANSACTION ISOLATION LEVEL REPEATABLE READ READ ONLY",
+			target: ANSIDialect,
+			result: "START TRANSACTION ISOLATION LEVEL REPEATABLE READ READ ONLY;",
+		},
+		{
+			source: "BEGIN ISOLATION LEVEL SERIALIZABLE",
+			target: MySQLDialect,
+			err:    true,
+		},
+		{
+			source: "ROLLBACK TO SAVEPOINT a",
+			target: MySQLDialect,
+			result: "ROLLBACK TO SAVEPOINT a;",
+		},
+		{
+			source: "RELEASE SAVEPOINT a",
+			target: SQLiteDialect,
+			result: "RELEASE SAVEPOINT a;",
+		},
+		{
+			source: "RELEASE SAVEPOINT a",
+			target: SQLServerDialect,
+			err:    true,
+		},
+		{
+			source:  "SELECT id FROM users WHERE a = ? AND b = ?",
+			dialect: MySQLDialect,
+			target:  PostgreSQLDialect,
+			result:  "SELECT\n\tid\nFROM\n\tusers\nWHERE\n\t((a = $1) and (b = $2));",
+		},
+		{
+			source: "SELECT id FROM users WHERE a = $1 AND b = $2",
+			target: MySQLDialect,
+			result: "SELECT\n\tid\nFROM\n\tusers\nWHERE\n\t((a = ?) and (b = ?));",
+		},
+		{
+			source:  "SELECT id FROM users WHERE a = :a AND b = @b",
+			dialect: SQLiteDialect,
+			target:  SQLServerDialect,
+			result:  "SELECT\n\tid\nFROM\n\tusers\nWHERE\n\t((a = @a) and (b = @b));",
+		},
+		{
+			source:  "SELECT id FROM users WHERE a = @a AND b = ?",
+			dialect: SQLServerDialect,
+			target:  ANSIDialect,
+			result:  "SELECT\n\tid\nFROM\n\tusers\nWHERE\n\t((a = :a) and (b = ?));",
+		},
+		{
+			source: "SELECT id FROM users WHERE a = :a AND b = $1",
+			target: SQLiteDialect,
+			result: "SELECT\n\tid\nFROM\n\tusers\nWHERE\n\t((a = :a) and (b = $1));",
+		},
+		// failures
+		{
+			source: "SELECT id FROM users WHERE a = $2 AND b = $1",
+			target: MySQLDialect,
+			err:    true,
+		},
+		{
+			source: "SELECT id FROM users WHERE a = $1 OR b = $1",
+			target: ANSIDialect,
+			err:    true,
+		},
+		{
+			source:  "SELECT id FROM users WHERE id = :id",
+			dialect: SQLiteDialect,
+			target:  PostgreSQLDialect,
+			err:     true,
+		},
+		{
+			source:  "SELECT id FROM users WHERE id = @id",
+			dialect: SQLServerDialect,
+			target:  MySQLDialect,
+			err:     true,
+		},
+		{
+			source: "SELECT id FROM users WHERE id = $1",
+			target: SQLServerDialect,
+			err:    true,
+		},
+		{
+			source: "DELETE FROM users RETURNING id",
+			target: MySQLDialect,
+			err:    true,
+		},
+		{
+			source: "INSERT INTO users VALUES (1) ON CONFLICT DO NOTHING",
+			target: SQLServerDialect,
+			err:    true,
+		},
+		{
+			source:  "INSERT INTO users VALUES (1) ON DUPLICATE KEY UPDATE id = 2",
+			dialect: MySQLDialect,
+			target:  PostgreSQLDialect,
+			err:     true,
+		},
+		{
+			source: "CREATE MATERIALIZED VIEW v AS SELECT id FROM users",
+			target: SQLiteDialect,
+			err:    true,
+		},
+		{
+			source: "SELECT id FROM users LIMIT 10 OFFSET 20",
+			target: SQLServerDialect,
+			err:    true,
+		},
+		{
+			source: "SELECT INTERVAL '1 day 2 hours'",
+			target: MySQLDialect,
+			err:    true,
+		},
+		{
+			source: "SELECT B'101'",
+			target: SQLiteDialect,
+			err:    true,
+		},
+		{
+			source:  "UPDATE users SET name = VALUES(name)",
+			dialect: MySQLDialect,
+			target:  PostgreSQLDialect,
+			err:     true,
+		},
+		{
+			source: "DROP INDEX age_idx",
+			target: MySQLDialect,
+			err:    true,
+		},
+		{
+			source: "BEGIN ISOLATION LEVEL SERIALIZABLE",
+			target: SQLiteDialect,
+			err:    true,
+		},
+		{
+			source: "CREATE TABLE t (id INT GENERATED BY DEFAULT AS IDENTITY)",
+			target: SQLiteDialect,
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		parser := Parser{HelpMessagesDisabled: true, Dialect: test.dialect}
+		ast, err := parser.Parse(test.source)
+		if !assert.Nil(t, err, test.source) {
+			continue
+		}
+
+		code, err := ast.GenerateCodeFor(test.target)
+		assert.Equal(t, test.err, err != nil, test.source)
+		assert.Equal(t, test.result, code, test.source)
+	}
+}
+
+func TestStatement_Pos(t *testing.T) {
+	source := `SELECT größe AS n
+FROM users u
+WHERE id = 1;
+DELETE FROM sessions`
+
+	parser := Parser{HelpMessagesDisabled: true}
+	ast, err := parser.Parse(source)
+	if !assert.Nil(t, err) || !assert.Len(t, ast.Statements, 2) {
+		return
+	}
+
+	slct := ast.Statements[0].SelectStatement
+	tests := []struct {
+		node Node
+		pos  Pos
+		end  Pos
+		text string
+	}{
+		{ast.Statements[0], Pos{0, 1, 1}, Pos{45, 3, 13}, "SELECT größe AS n\nFROM users u\nWHERE id = 1"},
+		{slct.Items[0], Pos{7, 1, 8}, Pos{19, 1, 18}, "größe AS n"},
+		{slct.Items[0].Alias, Pos{18, 1, 17}, Pos{19, 1, 18}, "n"},
+		{slct.From, Pos{25, 2, 6}, Pos{32, 2, 13}, "users u"},
+		{slct.Where, Pos{39, 3, 7}, Pos{45, 3, 13}, "id = 1"},
+		{slct.Where.(*BinaryExpression).Right, Pos{44, 3, 12}, Pos{45, 3, 13}, "1"},
+		{ast.Statements[1], Pos{47, 4, 1}, Pos{67, 4, 21}, "DELETE FROM sessions"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.pos, test.node.Pos(), test.text)
+		assert.Equal(t, test.end, test.node.End(), test.text)
+		assert.Equal(t, test.text, source[test.pos.Offset:test.end.Offset])
+	}
+}
+
+func TestStatement_Pos_nodes(t *testing.T) {
+	source := `CREATE TABLE t1 (c1 INT PRIMARY KEY);
+CREATE UNIQUE INDEX i1 ON t2 USING m1 (c2 DESC, (c3 + 1)) INCLUDE (c4) WHERE c5 = 1;
+CREATE VIEW v1 (c6) AS SELECT c7 AS "Größe", * FROM s.t4 x WHERE c8 = DATE '2024-01-01' LIMIT 5 OFFSET $1;
+INSERT INTO t5 (c8) VALUES (E'\n', DEFAULT) ON CONFLICT (c10) DO UPDATE SET c11 = 'ü' WHERE c13 = 1 RETURNING c14;
+UPDATE t6 SET a = INTERVAL '1' DAY WHERE c15 = :id;
+BEGIN ISOLATION LEVEL READ COMMITTED;
+ROLLBACK TO s2`
+
+	parser := Parser{HelpMessagesDisabled: true}
+	ast, err := parser.Parse(source)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	Inspect(ast, func(n Node) bool {
+		if n == nil {
+			return false
+		}
+
+		pos, end := n.Pos(), n.End()
+		if assert.True(t, pos.IsValid(), "%T", n) && assert.True(t, pos.Offset < end.Offset, "%T", n) {
+			text := source[pos.Offset:end.Offset]
+			line := strings.Split(source, "\n")[pos.Line-1]
+			prefix := []rune(line)[:pos.Col-1]
+			assert.True(t, strings.HasPrefix(line[len(string(prefix)):], strings.SplitN(text, "\n", 2)[0]), "%T %s", n, text)
+		}
+
+		return true
+	})
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines around each hunk
+const diffContext = 3
+
+// edit is a line of a diff, op being ' ', '-' or '+', and line keeping its
+// line ending so that a last line missing one differs from the same line
+// with one
+type edit struct {
+	op   byte
+	line string
+}
+
+// diffLines computes the edits turning a into b from their longest common
+// subsequence of lines
+func diffLines(a, b []string) []edit {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	edits := []edit{}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			edits = append(edits, edit{' ', a[i]})
+			i++
+			j++
+		case j == len(b) || i < len(a) && lcs[i+1][j] >= lcs[i][j+1]:
+			edits = append(edits, edit{'-', a[i]})
+			i++
+		default:
+			edits = append(edits, edit{'+', b[j]})
+			j++
+		}
+	}
+
+	return edits
+}
+
+// splitLines splits s into lines, with their line endings
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// unifiedDiff renders the changes from before to after in the unified
+// format, or returns "" when they are equal
+func unifiedDiff(name, before, after string) string {
+	if before == after {
+		return ""
+	}
+
+	edits := diffLines(splitLines(before), splitLines(after))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", name, name+".formatted")
+
+	// line numbers in a and b of the next edit
+	aLine, bLine := 1, 1
+	for start := 0; start < len(edits); {
+		if edits[start].op == ' ' {
+			aLine++
+			bLine++
+			start++
+			continue
+		}
+
+		// extend the hunk until more than twice the context separates changes
+		end := start
+		for unchanged := 0; end < len(edits) && unchanged <= 2*diffContext; end++ {
+			if edits[end].op == ' ' {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+		}
+
+		for end > start && edits[end-1].op == ' ' {
+			end--
+		}
+
+		from := start - diffContext
+		if from < 0 {
+			from = 0
+		}
+
+		to := end + diffContext
+		if to > len(edits) {
+			to = len(edits)
+		}
+
+		hunkA, hunkB := aLine-(start-from), bLine-(start-from)
+		aCount, bCount := 0, 0
+		var lines strings.Builder
+		for _, e := range edits[from:to] {
+			if e.op != '+' {
+				aCount++
+			}
+
+			if e.op != '-' {
+				bCount++
+			}
+
+			lines.WriteString(string(e.op) + e.line)
+			if !strings.HasSuffix(e.line, "\n") {
+				lines.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(hunkA, aCount), hunkRange(hunkB, bCount))
+		sb.WriteString(lines.String())
+
+		for _, e := range edits[start:to] {
+			if e.op != '+' {
+				aLine++
+			}
+
+			if e.op != '-' {
+				bLine++
+			}
+		}
+
+		start = to
+	}
+
+	return sb.String()
+}
+
+// hunkRange formats the lines of a hunk, the start of empty ranges being the
+// line before them
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start-1)
+	}
+
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+
+	return fmt.Sprintf("%d,%d", start, count)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		before string
+		after  string
+		diff   string
+	}{
+		{
+			before: "a\nb\n",
+			after:  "a\nb\n",
+			diff:   "",
+		},
+		{
+			before: "a\nb\nc\n",
+			after:  "a\nx\nc\n",
+			diff: `--- q.sql
++++ q.sql.formatted
+@@ -1,3 +1,3 @@
+ a
+-b
++x
+ c
+`,
+		},
+		{
+			before: "",
+			after:  "a\n",
+			diff: `--- q.sql
++++ q.sql.formatted
+@@ -0,0 +1 @@
++a
+`,
+		},
+		{
+			before: "a\nb",
+			after:  "a\nb\n",
+			diff: `--- q.sql
++++ q.sql.formatted
+@@ -1,2 +1,2 @@
+ a
+-b
+\ No newline at end of file
++b
+`,
+		},
+		{
+			before: "a\nb\n",
+			after:  "x\nb",
+			diff: `--- q.sql
++++ q.sql.formatted
+@@ -1,2 +1,2 @@
+-a
+-b
++x
++b
+\ No newline at end of file
+`,
+		},
+		{
+			// changes far apart make separate hunks
+			before: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			after:  "0\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n",
+			diff: `--- q.sql
++++ q.sql.formatted
+@@ -1,4 +1,4 @@
+-1
++0
+ 2
+ 3
+ 4
+@@ -9,4 +9,3 @@
+ 9
+ 10
+ 11
+-12
+`,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.diff, unifiedDiff("q.sql", test.before, test.after), test.before)
+	}
+}
@@ -0,0 +1,245 @@
+// Command sqlfmt formats SQL files.
+//
+// Without paths it formats standard input. Directories are walked for .sql
+// files. Comments are kept next to the tokens they surround, and blank lines
+// between statements are kept. By default the formatted sources are written
+// to standard output.
+//
+// Usage:
+//
+//	sqlfmt [flags] [path ...]
+//
+// The exit status is 1 when a file isn't formatted and wasn't rewritten with
+// -w, and 2 on errors, so that `sqlfmt -l migrations` can gate commits.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gosqlshell "github.com/isurusiri/go-sql-shell"
+)
+
+var dialects = map[string]gosqlshell.Dialect{
+	"postgres":  gosqlshell.PostgreSQLDialect,
+	"mysql":     gosqlshell.MySQLDialect,
+	"sqlite":    gosqlshell.SQLiteDialect,
+	"ansi":      gosqlshell.ANSIDialect,
+	"sqlserver": gosqlshell.SQLServerDialect,
+}
+
+var keywordCases = map[string]gosqlshell.KeywordCase{
+	"default": gosqlshell.DefaultCase,
+	"upper":   gosqlshell.UpperCase,
+	"lower":   gosqlshell.LowerCase,
+}
+
+// formatter formats sources with the options given on the command line
+type formatter struct {
+	dialect gosqlshell.Dialect
+	printer gosqlshell.Printer
+	list    bool
+	diff    bool
+	write   bool
+	stdout  io.Writer
+	// unformatted is set when a source isn't formatted and wasn't rewritten
+	unformatted bool
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs sqlfmt with args, returning its exit status
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("sqlfmt", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: sqlfmt [flags] [path ...]")
+		flags.PrintDefaults()
+	}
+
+	list := flags.Bool("l", false, "list files whose formatting differs")
+	diff := flags.Bool("d", false, "display diffs instead of rewriting files")
+	write := flags.Bool("w", false, "write the result to the source file instead of standard output")
+	dialect := flags.String("dialect", "postgres", "SQL dialect: postgres, mysql, sqlite, ansi or sqlserver")
+	keywords := flags.String("keywords", "default", "keyword case: default, upper or lower")
+	indent := flags.Int("indent", 0, "number of spaces to indent with, tabs when 0")
+	width := flags.Int("width", 0, "column to wrap lines at, no wrapping when 0")
+	commaFirst := flags.Bool("comma-first", false, "start wrapped list items with their comma")
+	minimalParens := flags.Bool("minimal-parens", false, "only parenthesize where precedence requires it")
+	compact := flags.Bool("compact", false, "print every statement on a single line")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	d, ok := dialects[*dialect]
+	if !ok {
+		fmt.Fprintf(stderr, "sqlfmt: unknown dialect %q\n", *dialect)
+		return 2
+	}
+
+	kc, ok := keywordCases[*keywords]
+	if !ok {
+		fmt.Fprintf(stderr, "sqlfmt: unknown keyword case %q\n", *keywords)
+		return 2
+	}
+
+	f := &formatter{
+		dialect: d,
+		printer: gosqlshell.Printer{
+			Dialect:            d,
+			KeywordCase:        kc,
+			IndentWidth:        *indent,
+			Width:              *width,
+			CommaFirst:         *commaFirst,
+			MinimalParentheses: *minimalParens,
+			Compact:            *compact,
+			Comments:           true,
+		},
+		list:   *list,
+		diff:   *diff,
+		write:  *write,
+		stdout: stdout,
+	}
+
+	if flags.NArg() == 0 {
+		if f.write {
+			fmt.Fprintln(stderr, "sqlfmt: cannot use -w with standard input")
+			return 2
+		}
+
+		source, err := io.ReadAll(stdin)
+		if err == nil {
+			err = f.process("<stdin>", source, nil)
+		}
+
+		if err != nil {
+			fmt.Fprintf(stderr, "sqlfmt: %s\n", err)
+			return 2
+		}
+
+		return f.status()
+	}
+
+	failed := false
+	for _, path := range flags.Args() {
+		if err := f.processPath(path); err != nil {
+			fmt.Fprintf(stderr, "sqlfmt: %s\n", err)
+			failed = true
+		}
+	}
+
+	if failed {
+		return 2
+	}
+
+	return f.status()
+}
+
+func (f *formatter) status() int {
+	if f.unformatted {
+		return 1
+	}
+
+	return 0
+}
+
+// processPath formats the file at path, or the .sql files below it when
+// it's a directory
+func (f *formatter) processPath(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return f.processFile(path, info.Mode())
+	}
+
+	return filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || filepath.Ext(p) != ".sql" {
+			return nil
+		}
+
+		return f.processFile(p, info.Mode())
+	})
+}
+
+func (f *formatter) processFile(path string, mode os.FileMode) error {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return f.process(path, source, func(formatted []byte) error {
+		return os.WriteFile(path, formatted, mode.Perm())
+	})
+}
+
+// process formats the source read from name, writing it back with write
+// when it differs and -w is set
+func (f *formatter) process(name string, source []byte, write func([]byte) error) error {
+	formatted, err := f.format(source)
+	if err != nil {
+		return fmt.Errorf("%s: %s", name, err)
+	}
+
+	changed := !bytes.Equal(source, formatted)
+	if changed && f.list {
+		fmt.Fprintln(f.stdout, name)
+	}
+
+	if changed && f.diff {
+		fmt.Fprint(f.stdout, unifiedDiff(name, string(source), string(formatted)))
+	}
+
+	if changed && f.write {
+		if err := write(formatted); err != nil {
+			return err
+		}
+	} else if changed {
+		f.unformatted = true
+	}
+
+	if !f.list && !f.diff && !f.write {
+		_, err = f.stdout.Write(formatted)
+	}
+
+	return err
+}
+
+// format parses and prints source, leaving sources without statements, such
+// as blank files or those only holding comments, as they are
+func (f *formatter) format(source []byte) ([]byte, error) {
+	parser := gosqlshell.Parser{
+		Dialect:              f.dialect,
+		KeepComments:         true,
+		HelpMessagesDisabled: true,
+	}
+
+	ast, err := parser.Parse(string(source))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ast.Statements) == 0 {
+		return source, nil
+	}
+
+	code, err := f.printer.Print(ast)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(strings.TrimRight(code, "\n") + "\n"), nil
+}
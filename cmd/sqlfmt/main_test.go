@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const unformatted = `-- add users
+create table users (id int primary key, -- surrogate
+  name text); -- v1
+
+SELECT id, /* the name */ name FROM users WHERE id = 1;
+`
+
+const formatted = `-- add users
+CREATE TABLE users (
+	id INT PRIMARY KEY, -- surrogate
+	name TEXT
+); -- v1
+
+SELECT
+	id, /* the name */
+	name
+FROM
+	users
+WHERE
+	(id = 1);
+`
+
+func TestRun_stdin(t *testing.T) {
+	tests := []struct {
+		args   []string
+		stdin  string
+		stdout string
+		stderr string
+		status int
+	}{
+		{
+			stdin:  unformatted,
+			stdout: formatted,
+			status: 1,
+		},
+		{
+			stdin:  formatted,
+			stdout: formatted,
+		},
+		{
+			args:   []string{"-l"},
+			stdin:  unformatted,
+			stdout: "<stdin>\n",
+			status: 1,
+		},
+		{
+			args:  []string{"-l"},
+			stdin: formatted,
+		},
+		{
+			args:  []string{"-d"},
+			stdin: "select 1;\n",
+			stdout: `--- <stdin>
++++ <stdin>.formatted
+@@ -1 +1,2 @@
+-select 1;
++SELECT
++	1;
+`,
+			status: 1,
+		},
+		{
+			args:   []string{"-compact", "-keywords", "lower", "-dialect", "mysql"},
+			stdin:  "SELECT `id` FROM t LIMIT 1, 2",
+			stdout: "select id from t limit 1, 2;\n",
+			status: 1,
+		},
+		{
+			// nothing to format
+			stdin:  "-- just a comment",
+			stdout: "-- just a comment",
+		},
+		{
+			stdin:  "SELECT FROM;",
+			stderr: "sqlfmt: <stdin>: Failed to parse, expected statement\n",
+			status: 2,
+		},
+		{
+			args:   []string{"-w"},
+			stderr: "sqlfmt: cannot use -w with standard input\n",
+			status: 2,
+		},
+		{
+			args:   []string{"-dialect", "oracle"},
+			stderr: "sqlfmt: unknown dialect \"oracle\"\n",
+			status: 2,
+		},
+	}
+
+	for _, test := range tests {
+		var stdout, stderr bytes.Buffer
+		status := run(test.args, strings.NewReader(test.stdin), &stdout, &stderr)
+		assert.Equal(t, test.status, status, test.stdin)
+		assert.Equal(t, test.stdout, stdout.String(), test.stdin)
+		assert.Equal(t, test.stderr, stderr.String(), test.stdin)
+	}
+}
+
+func TestRun_files(t *testing.T) {
+	dir, err := os.MkdirTemp("", "sqlfmt")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"001_users.sql":        unformatted,
+		"002_posts.sql":        formatted,
+		"nested/003_tags.sql":  "select 1",
+		"nested/README":        "select 1",
+		"nested/004_empty.sql": "",
+	}
+	for name, source := range files {
+		path := filepath.Join(dir, name)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.Nil(t, os.WriteFile(path, []byte(source), 0644))
+	}
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 1, run([]string{"-l", dir}, nil, &stdout, &stderr))
+	assert.Equal(t, filepath.Join(dir, "001_users.sql")+"\n"+filepath.Join(dir, "nested/003_tags.sql")+"\n", stdout.String())
+	assert.Empty(t, stderr.String())
+
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"-l", "-w", dir}, nil, &stdout, &stderr))
+	assert.Equal(t, filepath.Join(dir, "001_users.sql")+"\n"+filepath.Join(dir, "nested/003_tags.sql")+"\n", stdout.String())
+
+	for name, source := range map[string]string{
+		"001_users.sql":       formatted,
+		"nested/003_tags.sql": "SELECT\n\t1;\n",
+		"nested/README":       "select 1",
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		assert.Nil(t, err, name)
+		assert.Equal(t, source, string(data), name)
+	}
+
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"-l", dir}, nil, &stdout, &stderr))
+	assert.Empty(t, stdout.String())
+
+	assert.Equal(t, 2, run([]string{filepath.Join(dir, "missing.sql")}, nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "missing.sql")
+}
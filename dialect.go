@@ -0,0 +1,120 @@
+package gosqlshell
+
+// Dialect selects the flavour of SQL accepted by the Parser
+type Dialect uint
+
+const (
+	// PostgreSQLDialect is the default dialect
+	PostgreSQLDialect Dialect = iota
+	// MySQLDialect enables MySQL specific syntax
+	MySQLDialect
+	// SQLiteDialect enables SQLite specific syntax
+	SQLiteDialect
+	// ANSIDialect only accepts standard SQL
+	ANSIDialect
+	// SQLServerDialect enables SQL Server specific syntax
+	SQLServerDialect
+)
+
+func (d Dialect) String() string {
+	switch d {
+	case PostgreSQLDialect:
+		return "PostgreSQL"
+	case MySQLDialect:
+		return "MySQL"
+	case SQLiteDialect:
+		return "SQLite"
+	case ANSIDialect:
+		return "ANSI SQL"
+	case SQLServerDialect:
+		return "SQL Server"
+	}
+
+	return "?unknown?"
+}
+
+// dialectKeywords lists the keywords only some dialects know about, they
+// lex as plain identifiers in the others
+var dialectKeywords = map[keyword][]Dialect{
+	materializedKeyword:  {PostgreSQLDialect},
+	refreshKeyword:       {PostgreSQLDialect},
+	includeKeyword:       {PostgreSQLDialect, SQLServerDialect},
+	conflictKeyword:      {PostgreSQLDialect, SQLiteDialect},
+	nothingKeyword:       {PostgreSQLDialect, SQLiteDialect},
+	returningKeyword:     {PostgreSQLDialect, SQLiteDialect},
+	duplicateKeyword:     {MySQLDialect},
+	limitKeyword:         {PostgreSQLDialect, MySQLDialect, SQLiteDialect},
+	fetchKeyword:         {PostgreSQLDialect, ANSIDialect, SQLServerDialect},
+	serialKeyword:        {PostgreSQLDialect},
+	generatedKeyword:     {PostgreSQLDialect, ANSIDialect},
+	identityKeyword:      {PostgreSQLDialect, ANSIDialect},
+	autoIncrementKeyword: {MySQLDialect},
+	autoincrementKeyword: {SQLiteDialect},
+}
+
+func (d Dialect) hasKeyword(k keyword) bool {
+	dialects, ok := dialectKeywords[k]
+	if !ok {
+		return true
+	}
+
+	for _, dialect := range dialects {
+		if dialect == d {
+			return true
+		}
+	}
+
+	return false
+}
+
+// identifierQuotes lists the characters opening a quoted identifier, with
+// the character closing it
+func (d Dialect) identifierQuotes() map[byte]byte {
+	switch d {
+	case MySQLDialect:
+		return map[byte]byte{'`': '`'}
+	case SQLiteDialect:
+		return map[byte]byte{'"': '"', '`': '`', '[': ']'}
+	case SQLServerDialect:
+		return map[byte]byte{'"': '"', '[': ']'}
+	}
+
+	return map[byte]byte{'"': '"'}
+}
+
+// stringQuotes lists the characters delimiting strings, MySQL also takes
+// double quotes
+func (d Dialect) stringQuotes() []byte {
+	if d == MySQLDialect {
+		return []byte{'\'', '"'}
+	}
+
+	return []byte{'\''}
+}
+
+// backslashEscapes tells whether backslashes escape characters in strings
+func (d Dialect) backslashEscapes() bool {
+	return d == MySQLDialect
+}
+
+// hasStringPrefix tells whether prefixed strings like E'...' or X'...' and
+// dollar quoted strings are supported
+func (d Dialect) hasStringPrefix(prefix byte) bool {
+	switch prefix {
+	case 'E', '$':
+		return d == PostgreSQLDialect
+	case 'B':
+		return d != SQLiteDialect && d != SQLServerDialect
+	case 'N':
+		return d != SQLiteDialect
+	case 'X':
+		return d != SQLServerDialect
+	}
+
+	return true
+}
+
+// hasLimitComma tells whether LIMIT offset, count is supported
+func (d Dialect) hasLimitComma() bool {
+	return d == MySQLDialect || d == SQLiteDialect
+}
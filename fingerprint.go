@@ -0,0 +1,70 @@
+package gosqlshell
+
+import (
+	"encoding/json"
+	"hash/fnv"
+)
+
+// Fingerprint normalizes stmt so that statements only differing in their
+// constants, layout or keyword case come out the same, returning the
+// normalized SQL along with a 64-bit hash of the normalized Ast.
+//
+// Literals, typed literals and parameters become ? placeholders, NULL and
+// DEFAULT being kept, and IN lists only holding placeholders collapse to a
+// single one, so that a IN (1) and a IN (1, 2, 3) come out the same. The
+// SQL is printed on a single line in upper case, and the hash is the FNV-1a
+// hash of the Ast's JSON encoding, stable for a given JSONVersion. stmt
+// itself is left untouched.
+func Fingerprint(stmt *Statement) (string, uint64, error) {
+	var normalized Statement
+	if err := clone(stmt, &normalized); err != nil {
+		return "", 0, err
+	}
+
+	Apply(&normalized, func(c *Cursor) bool {
+		if s, ok := c.Node().(interface{ setSpan(pos, end Pos) }); ok {
+			s.setSpan(Pos{}, Pos{})
+		}
+
+		switch n := c.Node().(type) {
+		case *Literal:
+			if n.Kind != NullLiteral && n.Kind != DefaultLiteral {
+				c.Replace(&ParameterExpression{Placeholder: "?"})
+			}
+		case *TypedLiteral, *ParameterExpression:
+			c.Replace(&ParameterExpression{Placeholder: "?"})
+			return false
+		}
+
+		return true
+	}, func(c *Cursor) bool {
+		if in, ok := c.Node().(*InExpression); ok && placeholders(in.List) {
+			in.List = in.List[:1]
+		}
+
+		return true
+	})
+
+	g := &generator{printer: Printer{KeywordCase: UpperCase, Compact: true}, verbatimParameters: true}
+	code := normalized.generateCode(g)
+
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return "", 0, err
+	}
+
+	h := fnv.New64a()
+	h.Write(data)
+	return code, h.Sum64(), nil
+}
+
+// placeholders tells whether every expression is a parameter
+func placeholders(exps []Expression) bool {
+	for _, exp := range exps {
+		if _, ok := exp.(*ParameterExpression); !ok {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,122 @@
+package gosqlshell
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		sources []string
+		dialect Dialect
+		result  string
+	}{
+		{
+			sources: []string{
+				"SELECT id FROM users WHERE name = 'alice' AND age = 30",
+				"select id\n  from users\n where name = 'bob' and age = $1",
+				"SELECT id FROM users WHERE name = E'x\\ny' AND age = -1.5",
+			},
+			result: "SELECT id FROM users WHERE ((name = ?) AND (age = ?));",
+		},
+		{
+			sources: []string{
+				"SELECT id FROM events WHERE at = DATE '2024-01-01' LIMIT 10",
+				"SELECT id FROM events WHERE at = :at LIMIT 20",
+			},
+			result: "SELECT id FROM events WHERE (at = ?) LIMIT ?;",
+		},
+		{
+			sources: []string{
+				"SELECT id FROM users WHERE id IN (1)",
+				"SELECT id FROM users WHERE id IN (1, 2, 3)",
+				"SELECT id FROM users WHERE id in ($1, 'x', DATE '2024-01-01', :id)",
+			},
+			result: "SELECT id FROM users WHERE (id IN (?));",
+		},
+		{
+			sources: []string{
+				"SELECT id FROM users WHERE id NOT IN (1, 2)",
+			},
+			result: "SELECT id FROM users WHERE (id NOT IN (?));",
+		},
+		{
+			sources: []string{
+				"SELECT id FROM users WHERE id IN (1, owner_id)",
+				"SELECT id FROM users WHERE id IN (2, owner_id)",
+			},
+			result: "SELECT id FROM users WHERE (id IN (?, owner_id));",
+		},
+		{
+			sources: []string{
+				"INSERT INTO t (a, b) VALUES (1, 'x')",
+				"INSERT INTO t (a, b) VALUES (2, 'y')",
+			},
+			result: "INSERT INTO t (a, b) VALUES (?, ?);",
+		},
+		{
+			// inserting more rows is a different statement
+			sources: []string{
+				"INSERT INTO t (a, b) VALUES (1, 'x'), (2, 'y')",
+			},
+			result: "INSERT INTO t (a, b) VALUES (?, ?), (?, ?);",
+		},
+		{
+			sources: []string{
+				"INSERT INTO t (a, b) VALUES (1, DEFAULT), (2, 'y'), (3, DEFAULT)",
+			},
+			result: "INSERT INTO t (a, b) VALUES (?, DEFAULT), (?, ?), (?, DEFAULT);",
+		},
+		{
+			sources: []string{
+				"UPDATE t SET a = NULL WHERE b = true",
+			},
+			result: "UPDATE t SET a = NULL WHERE (b = ?);",
+		},
+		{
+			sources: []string{
+				"SELECT id FROM t LIMIT 10, 5",
+			},
+			dialect: MySQLDialect,
+			result:  "SELECT id FROM t LIMIT ? OFFSET ?;",
+		},
+	}
+
+	hashes := map[uint64]string{}
+	for _, test := range tests {
+		for _, source := range test.sources {
+			parser := Parser{HelpMessagesDisabled: true, Dialect: test.dialect}
+			ast, err := parser.Parse(source)
+			if !assert.Nil(t, err, source) {
+				continue
+			}
+
+			before := ast.Statements[0].GenerateCode()
+			code, hash, err := Fingerprint(ast.Statements[0])
+			assert.Nil(t, err, source)
+			assert.Equal(t, test.result, code, source)
+			assert.Equal(t, before, ast.Statements[0].GenerateCode(), source)
+
+			if other, ok := hashes[hash]; ok {
+				assert.Equal(t, other, code, source)
+			}
+			hashes[hash] = code
+		}
+	}
+
+	assert.Len(t, hashes, len(tests))
+}
+
+func TestFingerprint_stable(t *testing.T) {
+	parser := Parser{HelpMessagesDisabled: true}
+	ast, err := parser.Parse("SELECT 1")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	code, hash, err := Fingerprint(ast.Statements[0])
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT ?;", code)
+	assert.Equal(t, uint64(0x422de88b20fe0ed3), hash)
+}
@@ -0,0 +1,301 @@
+package gosqlshell
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// generator threads the target dialect and the layout of the Printer
+// through code generation. Constructs the dialect can't express are still
+// generated as written, the first one being kept as err.
+type generator struct {
+	dialect Dialect
+	printer Printer
+	err     error
+	// positions numbers the positional parameters of the statement being
+	// generated, in the order Parameters lists them
+	positions map[*ParameterExpression]uint
+	// verbatimParameters keeps placeholders as written whatever the dialect
+	verbatimParameters bool
+}
+
+func (g *generator) unsupported(construct string) {
+	if g.err == nil {
+		g.err = fmt.Errorf("%s is not supported by %s", construct, g.dialect)
+	}
+}
+
+// numberParameters numbers the positional parameters of a statement or an
+// expression so that ? and $n placeholders can be translated into each other
+func (g *generator) numberParameters(root Node) {
+	g.positions = map[*ParameterExpression]uint{}
+	walkParameters(root, func(c *Cursor, param Parameter) {
+		if param.Name == "" {
+			g.positions[c.Node().(*ParameterExpression)] = uint(len(g.positions)) + 1
+		}
+	})
+}
+
+// generateParameter writes the placeholder the way the dialect expects it:
+// $n for PostgreSQL, ? for MySQL, @name for SQL Server and ? or :name for
+// ANSI SQL. SQLite accepts them all.
+func (g *generator) generateParameter(pe *ParameterExpression) string {
+	p := pe.Placeholder
+	if g.verbatimParameters {
+		return p
+	}
+
+	named := p[0] == ':' || p[0] == '@'
+
+	switch g.dialect {
+	case PostgreSQLDialect:
+		if named {
+			g.unsupported(fmt.Sprintf("named parameter %s", p))
+		} else if position, ok := g.positions[pe]; ok && p == "?" {
+			return fmt.Sprintf("$%d", position)
+		}
+	case MySQLDialect:
+		if named {
+			g.unsupported(fmt.Sprintf("named parameter %s", p))
+		} else if p[0] == '$' {
+			return g.generateQuestionMark(pe)
+		}
+	case ANSIDialect:
+		if p[0] == '@' {
+			return ":" + p[1:]
+		} else if p[0] == '$' {
+			return g.generateQuestionMark(pe)
+		}
+	case SQLServerDialect:
+		if !named {
+			g.unsupported(fmt.Sprintf("positional parameter %s", p))
+		} else {
+			return "@" + p[1:]
+		}
+	}
+
+	return p
+}
+
+// generateQuestionMark turns $n into ?, which only binds the same value
+// when $n is the n-th positional parameter of the statement
+func (g *generator) generateQuestionMark(pe *ParameterExpression) string {
+	if position, ok := g.positions[pe]; !ok || pe.Placeholder != fmt.Sprintf("$%d", position) {
+		g.unsupported(fmt.Sprintf("out of order parameter %s", pe.Placeholder))
+		return pe.Placeholder
+	}
+
+	return "?"
+}
+
+// require reports construct as unsupported unless the target is one of
+// dialects
+func (g *generator) require(construct string, dialects ...Dialect) bool {
+	for _, d := range dialects {
+		if d == g.dialect {
+			return true
+		}
+	}
+
+	g.unsupported(construct)
+	return false
+}
+
+// generateIdentifier emits the name bare when lexing it back gives the
+// same identifier, and quoted otherwise, e.g. for mixed case names, names
+// with spaces or names clashing with a keyword
+func (g *generator) generateIdentifier(name string) string {
+	if !identifierNeedsQuotes(name) {
+		return name
+	}
+
+	switch g.dialect {
+	case MySQLDialect:
+		return fmt.Sprintf("`%s`", strings.ReplaceAll(name, "`", "``"))
+	case SQLServerDialect:
+		return fmt.Sprintf("[%s]", strings.ReplaceAll(name, "]", "]]"))
+	}
+
+	return fmt.Sprintf("\"%s\"", strings.ReplaceAll(name, `"`, `""`))
+}
+
+func identifierNeedsQuotes(name string) bool {
+	if name == "" || name != foldIdentifier(name) || isKeyword(name) {
+		return true
+	}
+
+	for i, r := range name {
+		if i == 0 && !isIdentifierStart(r) || !isIdentifierPart(r) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (g *generator) generateIdentifierList(ids []*Identifier) string {
+	quoted := []string{}
+	for _, id := range ids {
+		quoted = append(quoted, id.generateCode(g))
+	}
+
+	return g.list(quoted)
+}
+
+// generateString quotes the body of a standard string, MySQL also needing
+// its backslashes escaped
+func (g *generator) generateString(prefix, body string) string {
+	body = escapeQuotes(body, false)
+	if g.dialect == MySQLDialect {
+		body = strings.ReplaceAll(body, `\`, `\\`)
+	}
+
+	return fmt.Sprintf("%s'%s'", prefix, body)
+}
+
+func (g *generator) generateLiteral(l *Literal) string {
+	switch l.Kind {
+	case StringLiteral:
+		return g.generateString("", l.Value)
+	case NullLiteral, DefaultLiteral:
+		return g.keyword(l.Value)
+	case EscapeStringLiteral:
+		if g.dialect == PostgreSQLDialect {
+			return fmt.Sprintf("E'%s'", escapeQuotes(l.Value, true))
+		}
+
+		return g.generateString("", unescapeString(l.Value))
+	case DollarStringLiteral:
+		if g.dialect == PostgreSQLDialect && dollarQuotable(l.Tag, l.Value) {
+			return fmt.Sprintf("$%s$%s$%s$", l.Tag, l.Value, l.Tag)
+		}
+
+		return g.generateString("", strings.ReplaceAll(l.Value, "'", "''"))
+	case HexStringLiteral:
+		// values set by hand that aren't hex digits are kept as strings
+		if !onlyDigits(l.Value, "0123456789abcdefABCDEF") {
+			return g.generateString("", l.Value)
+		}
+
+		if g.dialect == SQLServerDialect {
+			return "0x" + l.Value
+		}
+
+		return fmt.Sprintf("X'%s'", l.Value)
+	case BitStringLiteral:
+		if !onlyDigits(l.Value, "01") {
+			return g.generateString("", l.Value)
+		}
+
+		g.require("bit string", PostgreSQLDialect, MySQLDialect, ANSIDialect)
+		return fmt.Sprintf("B'%s'", l.Value)
+	case NationalStringLiteral:
+		// SQLite strings are all unicode already
+		if g.dialect == SQLiteDialect {
+			return g.generateString("", l.Value)
+		}
+
+		return g.generateString("N", l.Value)
+	case BoolLiteral:
+		if g.dialect == SQLServerDialect {
+			if l.Value == string(trueKeyword) {
+				return "1"
+			}
+
+			return "0"
+		}
+
+		return g.keyword(l.Value)
+	}
+
+	return l.Value
+}
+
+// dollarQuotable tells whether tag is a valid tag and value doesn't end
+// the dollar quoted string early
+func dollarQuotable(tag, value string) bool {
+	for i, c := range tag {
+		if i == 0 && !isIdentifierStart(c) && c != '_' || !isIdentifierPart(c) || c == '$' {
+			return false
+		}
+	}
+
+	delimiter := "$" + tag + "$"
+	return strings.Index(value+delimiter, delimiter) == len(value)
+}
+
+func onlyDigits(value, digits string) bool {
+	for _, c := range value {
+		if !strings.ContainsRune(digits, c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// escapeQuotes doubles the quotes of a string body that aren't doubled yet,
+// so that values set by hand can't end the string early. Quotes escaped by a
+// backslash are kept as they are when backslashes escape, a trailing
+// backslash being doubled so that it doesn't escape the closing quote.
+func escapeQuotes(body string, backslashes bool) string {
+	var b strings.Builder
+	for i := 0; i < len(body); i++ {
+		switch {
+		case backslashes && body[i] == '\\' && i+1 < len(body):
+			b.WriteString(body[i : i+2])
+			i++
+		case backslashes && body[i] == '\\':
+			b.WriteString(`\\`)
+		case strings.HasPrefix(body[i:], "''"):
+			b.WriteString("''")
+			i++
+		case body[i] == '\'':
+			b.WriteString("''")
+		default:
+			b.WriteByte(body[i])
+		}
+	}
+
+	return b.String()
+}
+
+// unescapeString resolves the backslash escapes of PostgreSQL E'...' strings
+// into the body of a standard string
+func unescapeString(body string) string {
+	var b strings.Builder
+	writeRune := func(r rune) {
+		b.WriteRune(r)
+		if r == '\'' {
+			b.WriteRune(r)
+		}
+	}
+
+	for s := body; len(s) > 0; {
+		if strings.HasPrefix(s, "''") {
+			writeRune('\'')
+			s = s[2:]
+			continue
+		}
+
+		if s[0] == '\\' && len(s) > 1 {
+			r, _, tail, err := strconv.UnquoteChar(s, '\'')
+			if err == nil {
+				writeRune(r)
+				s = tail
+				continue
+			}
+
+			// unknown escapes stand for the character itself
+			s = s[1:]
+		}
+
+		r, size := utf8.DecodeRuneInString(s)
+		writeRune(r)
+		s = s[size:]
+	}
+
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package gosqlshell
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerator_generateIdentifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		result  string
+	}{
+		{"userid", PostgreSQLDialect, "userid"},
+		{"UserId", PostgreSQLDialect, `"UserId"`},
+		{"user id", PostgreSQLDialect, `"user id"`},
+		{"select", PostgreSQLDialect, `"select"`},
+		{"level", PostgreSQLDialect, `"level"`},
+		{"1st", PostgreSQLDialect, `"1st"`},
+		{`say "hi"`, PostgreSQLDialect, `"say ""hi"""`},
+		{"", PostgreSQLDialect, `""`},
+		{"größe", PostgreSQLDialect, "größe"},
+		{"Größe", PostgreSQLDialect, `"Größe"`},
+		{"userid", MySQLDialect, "userid"},
+		{"User`s", MySQLDialect, "`User``s`"},
+		{"User]s", SQLServerDialect, "[User]]s]"},
+		{"UserId", SQLiteDialect, `"UserId"`},
+		{"UserId", ANSIDialect, `"UserId"`},
+	}
+
+	for _, test := range tests {
+		g := generator{dialect: test.dialect}
+		assert.Equal(t, test.result, g.generateIdentifier(test.name), test.name)
+	}
+}
+
+func TestUnescapeString(t *testing.T) {
+	tests := []struct {
+		body   string
+		result string
+	}{
+		{`a\nb`, "a\nb"},
+		{`it\'s`, "it''s"},
+		{`it''s`, "it''s"},
+		{`\x41\101é`, "AAé"},
+		{`\q`, "q"},
+		{`\\`, `\`},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.result, unescapeString(test.body), test.body)
+	}
+}
+
+func TestEscapeQuotes(t *testing.T) {
+	tests := []struct {
+		body        string
+		backslashes bool
+		result      string
+	}{
+		{"it's", false, "it''s"},
+		{"it''s", false, "it''s"},
+		{"'", false, "''"},
+		{"'''", false, "''''"},
+		{`it\'s`, false, `it\''s`},
+		{`it\'s`, true, `it\'s`},
+		{`it's\`, true, `it''s\\`},
+		{`a\\`, true, `a\\`},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.result, escapeQuotes(test.body, test.backslashes), test.body)
+	}
+}
+
+func TestGenerator_generateLiteral(t *testing.T) {
+	tests := []struct {
+		literal *Literal
+		dialect Dialect
+		result  string
+	}{
+		{&Literal{Kind: StringLiteral, Value: "it's"}, PostgreSQLDialect, "'it''s'"},
+		{&Literal{Kind: StringLiteral, Value: "x'; DROP TABLE users; --"}, PostgreSQLDialect, "'x''; DROP TABLE users; --'"},
+		{&Literal{Kind: StringLiteral, Value: `it's \`}, MySQLDialect, `'it''s \\'`},
+		{&Literal{Kind: NationalStringLiteral, Value: "it's"}, PostgreSQLDialect, "N'it''s'"},
+		{&Literal{Kind: EscapeStringLiteral, Value: "it's"}, PostgreSQLDialect, "E'it''s'"},
+		{&Literal{Kind: DollarStringLiteral, Value: "it's"}, SQLiteDialect, "'it''s'"},
+		{&Literal{Kind: EscapeStringLiteral, Value: `it's\`}, PostgreSQLDialect, `E'it''s\\'`},
+		{&Literal{Kind: DollarStringLiteral, Value: "it's", Tag: "fn"}, PostgreSQLDialect, "$fn$it's$fn$"},
+		{&Literal{Kind: DollarStringLiteral, Value: "x$$; DROP TABLE t; --"}, PostgreSQLDialect, "'x$$; DROP TABLE t; --'"},
+		{&Literal{Kind: DollarStringLiteral, Value: "a$"}, PostgreSQLDialect, "'a$'"},
+		{&Literal{Kind: DollarStringLiteral, Value: "a", Tag: "1$"}, PostgreSQLDialect, "'a'"},
+		{&Literal{Kind: HexStringLiteral, Value: "FF"}, SQLServerDialect, "0xFF"},
+		{&Literal{Kind: HexStringLiteral, Value: "FF'; --"}, PostgreSQLDialect, "'FF''; --'"},
+		{&Literal{Kind: BitStringLiteral, Value: "0101"}, PostgreSQLDialect, "B'0101'"},
+		{&Literal{Kind: BitStringLiteral, Value: "2"}, PostgreSQLDialect, "'2'"},
+	}
+
+	for _, test := range tests {
+		g := generator{dialect: test.dialect}
+		assert.Equal(t, test.result, g.generateLiteral(test.literal), test.literal.Value)
+	}
+}
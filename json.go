@@ -0,0 +1,1022 @@
+package gosqlshell
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// JSONVersion is the version of the JSON schema written by Ast.MarshalJSON.
+//
+// An Ast is written as an object holding the schema "version" and its
+// "statements". Every node, statements included, is an object naming its
+// type in "node", e.g. "SelectStatement" or "Identifier", with its "pos" and
+// "end" positions as {"offset": 0, "line": 1, "col": 1} objects, see Pos.
+// Its fields follow, keyed by their name in lower camel case, e.g.
+// "onConflict", and left out when they hold their zero value. Expressions
+// are nodes like any other, the embedded expression of select items and
+// index elements is keyed "expression", and the values of INSERT statements
+// are arrays of rows. Literal kinds are written as "string", "numeric",
+// "bool", "null", "default", "escapeString", "dollarString", "hexString",
+// "bitString" and "nationalString".
+//
+// Fields generated as written, such as operators, placeholders, numbers and
+// data types, are rejected when decoding unless they hold what the parser
+// accepts. So are nodes missing a child the parser requires, e.g. a table
+// without a name, and lists holding null.
+//
+// For example SELECT 1 is written as:
+//
+//	{"version":1,"statements":[{"node":"SelectStatement",
+//	"pos":{"offset":0,"line":1,"col":1},"end":{"offset":8,"line":1,"col":9},
+//	"items":[{"node":"SelectItem","pos":{"offset":7,"line":1,"col":8},
+//	"end":{"offset":8,"line":1,"col":9},"expression":{"node":"Literal",
+//	"pos":{"offset":7,"line":1,"col":8},"end":{"offset":8,"line":1,"col":9},
+//	"kind":"numeric","value":"1"}}]}]}
+const JSONVersion = 1
+
+var literalKindNames = []string{
+	StringLiteral:         "string",
+	NumericLiteral:        "numeric",
+	BoolLiteral:           "bool",
+	NullLiteral:           "null",
+	DefaultLiteral:        "default",
+	EscapeStringLiteral:   "escapeString",
+	DollarStringLiteral:   "dollarString",
+	HexStringLiteral:      "hexString",
+	BitStringLiteral:      "bitString",
+	NationalStringLiteral: "nationalString",
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (k LiteralKind) MarshalText() ([]byte, error) {
+	if int(k) >= len(literalKindNames) {
+		return nil, fmt.Errorf("Unknown literal kind %d", k)
+	}
+
+	return []byte(literalKindNames[k]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (k *LiteralKind) UnmarshalText(text []byte) error {
+	for i, name := range literalKindNames {
+		if name == string(text) {
+			*k = LiteralKind(i)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Unknown literal kind %q", text)
+}
+
+// nodeTypes maps the node names of the schema to their types
+var nodeTypes = map[string]reflect.Type{}
+
+// expressionNodes are the names of the nodes that are expressions, select
+// items and index elements implementing Expression through embedding
+var expressionNodes = map[string]bool{
+	"Identifier":          true,
+	"QualifiedName":       true,
+	"Literal":             true,
+	"ParameterExpression": true,
+	"ValuesExpression":    true,
+	"BinaryExpression":    true,
+	"InExpression":        true,
+	"TypedLiteral":        true,
+}
+
+// statementKinds maps the node names of statements to their kind
+var statementKinds = map[string]AstKind{
+	"SelectStatement":                  SelectKind,
+	"CreateTableStatement":             CreateTableKind,
+	"CreateIndexStatement":             CreateIndexKind,
+	"DropTableStatement":               DropTableKind,
+	"InsertStatement":                  InsertKind,
+	"DropIndexStatement":               DropIndexKind,
+	"CreateViewStatement":              CreateViewKind,
+	"RefreshMaterializedViewStatement": RefreshMaterializedViewKind,
+	"DropViewStatement":                DropViewKind,
+	"UpdateStatement":                  UpdateKind,
+	"DeleteStatement":                  DeleteKind,
+	"BeginStatement":                   BeginKind,
+	"CommitStatement":                  CommitKind,
+	"RollbackStatement":                RollbackKind,
+	"SavepointStatement":               SavepointKind,
+	"ReleaseSavepointStatement":        ReleaseSavepointKind,
+}
+
+// statementOf wraps a statement node such as a *SelectStatement in a
+// Statement of the matching Kind
+func statementOf(n Node) (*Statement, bool) {
+	v := reflect.ValueOf(n)
+	if v.Kind() != reflect.Ptr {
+		return nil, false
+	}
+
+	name := v.Type().Elem().Name()
+	kind, ok := statementKinds[name]
+	if !ok || v.Type().Elem() != nodeTypes[name] {
+		return nil, false
+	}
+
+	stmt := &Statement{Kind: kind}
+	reflect.ValueOf(stmt).Elem().FieldByName(name).Set(v)
+	return stmt, true
+}
+
+// clone deep copies src into dst, a pointer to a value of the same type,
+// through their JSON encoding. Comments kept on statements aren't copied.
+func clone(src, dst interface{}) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, dst)
+}
+
+func init() {
+	for _, n := range []Node{
+		&Identifier{}, &QualifiedName{}, &Literal{}, &ParameterExpression{},
+		&ValuesExpression{}, &InExpression{},
+		&BinaryExpression{}, &TypedLiteral{}, &SelectItem{}, &TableReference{},
+		&LimitClause{}, &ColumnDefinition{}, &IndexElement{}, &SetClause{},
+		&OnConflictClause{}, &SelectStatement{}, &CreateTableStatement{},
+		&CreateIndexStatement{}, &DropIndexStatement{}, &DropTableStatement{},
+		&CreateViewStatement{}, &RefreshMaterializedViewStatement{},
+		&DropViewStatement{}, &InsertStatement{}, &UpdateStatement{},
+		&DeleteStatement{}, &BeginStatement{}, &CommitStatement{},
+		&RollbackStatement{}, &SavepointStatement{}, &ReleaseSavepointStatement{},
+	} {
+		typ := reflect.TypeOf(n).Elem()
+		nodeTypes[typ.Name()] = typ
+	}
+}
+
+var expressionType = reflect.TypeOf((*Expression)(nil)).Elem()
+
+// jsonName is the key of a field in the schema, e.g. onConflict
+func jsonName(field string) string {
+	r, size := utf8.DecodeRuneInString(field)
+	return string(unicode.ToLower(r)) + field[size:]
+}
+
+func (s *span) setSpan(pos, end Pos) {
+	s.pos, s.end = pos, end
+}
+
+func marshalNode(n Node) ([]byte, error) {
+	v := reflect.ValueOf(n).Elem()
+	pos, err := json.Marshal(n.Pos())
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := json.Marshal(n.End())
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `{"node":%q,"pos":%s,"end":%s`, v.Type().Name(), pos, end)
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.PkgPath != "" || v.Field(i).IsZero() {
+			continue
+		}
+
+		value, err := json.Marshal(v.Field(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Fprintf(&buf, `,%q:%s`, jsonName(field.Name), value)
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// nodeHeader holds the keys every node has
+type nodeHeader struct {
+	Node string `json:"node"`
+	Pos  Pos    `json:"pos"`
+	End  Pos    `json:"end"`
+}
+
+func unmarshalNode(data []byte, n Node) error {
+	var header nodeHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(n).Elem()
+	if header.Node != v.Type().Name() {
+		return fmt.Errorf("Expected a %s node, got %q", v.Type().Name(), header.Node)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	v.Set(reflect.Zero(v.Type()))
+	n.(interface{ setSpan(pos, end Pos) }).setSpan(header.Pos, header.End)
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		raw, ok := fields[jsonName(field.Name)]
+		if field.PkgPath != "" || !ok {
+			continue
+		}
+
+		if err := unmarshalValue(raw, v.Field(i)); err != nil {
+			return fmt.Errorf("%s.%s: %s", header.Node, jsonName(field.Name), err)
+		}
+	}
+
+	// fields generated as written must hold what the lexer would accept,
+	// and required children must be there
+	if val, ok := n.(validator); ok {
+		if err := val.validate(); err != nil {
+			return fmt.Errorf("%s.%s", header.Node, err)
+		}
+	}
+
+	return nil
+}
+
+// validator is implemented by the nodes holding fields that are generated
+// as written, or children they can't be generated without. The errors
+// start with the key of the field at fault.
+type validator interface {
+	validate() error
+}
+
+func invalid(key, value string) error {
+	return fmt.Errorf("%s: Invalid value %q", key, value)
+}
+
+// oneOf tells whether value is one of values
+func oneOf(value string, values ...string) bool {
+	for _, v := range values {
+		if value == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lexesAs tells whether the whole of source lexes with l
+func lexesAs(source string, l lexer) bool {
+	if source == "" {
+		return false
+	}
+
+	_, cur, ok := l(source, cursor{})
+	return ok && cur.pointer == uint(len(source))
+}
+
+func (l *Literal) validate() error {
+	valid := true
+	switch l.Kind {
+	case NumericLiteral:
+		valid = lexesAs(strings.TrimPrefix(l.Value, "-"), lexNumeric)
+	case BoolLiteral:
+		valid = oneOf(l.Value, string(trueKeyword), string(falseKeyword))
+	case NullLiteral:
+		valid = l.Value == "NULL"
+	case DefaultLiteral:
+		valid = l.Value == "DEFAULT"
+	case HexStringLiteral:
+		valid = onlyDigits(l.Value, "0123456789abcdefABCDEF")
+	case BitStringLiteral:
+		valid = onlyDigits(l.Value, "01")
+	case DollarStringLiteral:
+		if !dollarQuotable(l.Tag, l.Value) {
+			return invalid("tag", l.Tag)
+		}
+	}
+
+	if !valid {
+		return invalid("value", l.Value)
+	}
+
+	return nil
+}
+
+func (pe *ParameterExpression) validate() error {
+	if !lexesAs(pe.Placeholder, lexParameter) || pe.Placeholder == "$" {
+		return invalid("placeholder", pe.Placeholder)
+	}
+
+	return nil
+}
+
+func (be *BinaryExpression) validate() error {
+	if precedence(be.Operator) == 0 {
+		return invalid("operator", be.Operator)
+	}
+
+	if be.Left == nil {
+		return errors.New("left: Missing operand")
+	}
+
+	if be.Right == nil {
+		return errors.New("right: Missing operand")
+	}
+
+	return nil
+}
+
+func (ve *ValuesExpression) validate() error {
+	if ve.Column == nil {
+		return errors.New("column: Missing column")
+	}
+
+	return nil
+}
+
+func (in *InExpression) validate() error {
+	if in.Expression == nil {
+		return errors.New("expression: Missing operand")
+	}
+
+	if len(in.List) == 0 {
+		return errors.New("list: Missing expressions")
+	}
+
+	return nil
+}
+
+func (tl *TypedLiteral) validate() error {
+	if !oneOf(tl.Type, "DATE", "TIME", "TIMESTAMP", "INTERVAL") {
+		return invalid("type", tl.Type)
+	}
+
+	if _, ok := intervalUnits[strings.ToLower(tl.Unit)]; tl.Unit != "" && (!ok || tl.Unit != strings.ToUpper(tl.Unit)) {
+		return invalid("unit", tl.Unit)
+	}
+
+	if tl.Value == nil {
+		return errors.New("value: Missing literal")
+	}
+
+	if tl.Type == "INTERVAL" && tl.Interval == nil {
+		return errors.New("interval: Missing interval")
+	}
+
+	return nil
+}
+
+func (qn *QualifiedName) validate() error {
+	if len(qn.Parts) == 0 {
+		return errors.New("parts: Missing name")
+	}
+
+	return nil
+}
+
+func (si *SelectItem) validate() error {
+	if si.Expression == nil && !si.Asterisk {
+		return errors.New("expression: Missing expression")
+	}
+
+	return nil
+}
+
+func (tr *TableReference) validate() error {
+	if tr.Name == nil {
+		return errors.New("name: Missing name")
+	}
+
+	return nil
+}
+
+func (lc *LimitClause) validate() error {
+	if lc.Count == nil && lc.Offset == nil {
+		return errors.New("count: Missing count or offset")
+	}
+
+	return nil
+}
+
+func (ss *SelectStatement) validate() error {
+	if len(ss.Items) == 0 {
+		return errors.New("items: Missing items")
+	}
+
+	return nil
+}
+
+func (cd *ColumnDefinition) validate() error {
+	if cd.Name == nil {
+		return errors.New("name: Missing name")
+	}
+
+	if !isKeyword(strings.ToLower(cd.DataType)) || cd.DataType != strings.ToUpper(cd.DataType) {
+		return invalid("dataType", cd.DataType)
+	}
+
+	return nil
+}
+
+func (cts *CreateTableStatement) validate() error {
+	if cts.Name == nil {
+		return errors.New("name: Missing name")
+	}
+
+	return nil
+}
+
+func (ie *IndexElement) validate() error {
+	if ie.Expression == nil {
+		return errors.New("expression: Missing expression")
+	}
+
+	if !oneOf(ie.Order, "", "ASC", "DESC") {
+		return invalid("order", ie.Order)
+	}
+
+	if !oneOf(ie.Nulls, "", "FIRST", "LAST") {
+		return invalid("nulls", ie.Nulls)
+	}
+
+	return nil
+}
+
+func (cis *CreateIndexStatement) validate() error {
+	if cis.Name == nil {
+		return errors.New("name: Missing name")
+	}
+
+	if cis.Table == nil {
+		return errors.New("table: Missing table")
+	}
+
+	if len(cis.Elements) == 0 {
+		return errors.New("elements: Missing elements")
+	}
+
+	return nil
+}
+
+func (dis *DropIndexStatement) validate() error {
+	if dis.Name == nil {
+		return errors.New("name: Missing name")
+	}
+
+	return nil
+}
+
+func (dts *DropTableStatement) validate() error {
+	if dts.Name == nil {
+		return errors.New("name: Missing name")
+	}
+
+	return nil
+}
+
+func (cvs *CreateViewStatement) validate() error {
+	if cvs.Name == nil {
+		return errors.New("name: Missing name")
+	}
+
+	if cvs.Query == nil {
+		return errors.New("query: Missing query")
+	}
+
+	return nil
+}
+
+func (rmvs *RefreshMaterializedViewStatement) validate() error {
+	if rmvs.Name == nil {
+		return errors.New("name: Missing name")
+	}
+
+	return nil
+}
+
+func (dvs *DropViewStatement) validate() error {
+	if dvs.Name == nil {
+		return errors.New("name: Missing name")
+	}
+
+	return nil
+}
+
+func (sc *SetClause) validate() error {
+	if sc.Column == nil {
+		return errors.New("column: Missing column")
+	}
+
+	if sc.Value == nil {
+		return errors.New("value: Missing value")
+	}
+
+	return nil
+}
+
+func (occ *OnConflictClause) validate() error {
+	if !occ.DoNothing && len(occ.Set) == 0 {
+		return errors.New("set: Missing assignments")
+	}
+
+	return nil
+}
+
+func (is *InsertStatement) validate() error {
+	if is.Table == nil {
+		return errors.New("table: Missing table")
+	}
+
+	return nil
+}
+
+func (us *UpdateStatement) validate() error {
+	if us.Table == nil {
+		return errors.New("table: Missing table")
+	}
+
+	if len(us.Set) == 0 {
+		return errors.New("set: Missing assignments")
+	}
+
+	return nil
+}
+
+func (ds *DeleteStatement) validate() error {
+	if ds.Table == nil {
+		return errors.New("table: Missing table")
+	}
+
+	return nil
+}
+
+func (ss *SavepointStatement) validate() error {
+	if ss.Name == nil {
+		return errors.New("name: Missing name")
+	}
+
+	return nil
+}
+
+func (rss *ReleaseSavepointStatement) validate() error {
+	if rss.Name == nil {
+		return errors.New("name: Missing name")
+	}
+
+	return nil
+}
+
+func (bs *BeginStatement) validate() error {
+	if !oneOf(bs.IsolationLevel, "", "SERIALIZABLE", "REPEATABLE READ", "READ COMMITTED", "READ UNCOMMITTED") {
+		return invalid("isolationLevel", bs.IsolationLevel)
+	}
+
+	if !oneOf(bs.AccessMode, "", "READ ONLY", "READ WRITE") {
+		return invalid("accessMode", bs.AccessMode)
+	}
+
+	return nil
+}
+
+// unmarshalValue decodes data into v, picking the type of expressions from
+// their node name
+func unmarshalValue(data []byte, v reflect.Value) error {
+	switch {
+	case v.Type() == expressionType:
+		exp, err := unmarshalExpression(data)
+		if err != nil {
+			return err
+		}
+
+		if exp != nil {
+			v.Set(reflect.ValueOf(exp))
+		}
+
+		return nil
+	case v.Kind() == reflect.Slice:
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil || items == nil {
+			return err
+		}
+
+		s := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			// lists only ever hold nodes, never nothing
+			if string(item) == "null" {
+				return fmt.Errorf("Missing item %d", i)
+			}
+
+			if err := unmarshalValue(item, s.Index(i)); err != nil {
+				return err
+			}
+		}
+
+		v.Set(s)
+		return nil
+	}
+
+	return json.Unmarshal(data, v.Addr().Interface())
+}
+
+func unmarshalExpression(data []byte) (Expression, error) {
+	if string(data) == "null" {
+		return nil, nil
+	}
+
+	var header nodeHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+
+	typ, ok := nodeTypes[header.Node]
+	if !ok {
+		return nil, fmt.Errorf("Unknown node %q", header.Node)
+	}
+
+	if !expressionNodes[header.Node] {
+		return nil, fmt.Errorf("%s node is not an expression", header.Node)
+	}
+
+	exp := reflect.New(typ).Interface().(Expression)
+	return exp, json.Unmarshal(data, exp)
+}
+
+// MarshalJSON writes the statement of the given Kind
+func (s Statement) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.node())
+}
+
+// UnmarshalJSON reads a statement node, setting the Kind from it
+func (s *Statement) UnmarshalJSON(data []byte) error {
+	var header nodeHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return err
+	}
+
+	if _, ok := statementKinds[header.Node]; !ok {
+		return fmt.Errorf("Expected a statement node, got %q", header.Node)
+	}
+
+	n := reflect.New(nodeTypes[header.Node]).Interface().(Node)
+	if err := json.Unmarshal(data, n); err != nil {
+		return err
+	}
+
+	stmt, _ := statementOf(n)
+	*s = *stmt
+	return nil
+}
+
+type astJSON struct {
+	Version    int          `json:"version"`
+	Statements []*Statement `json:"statements"`
+}
+
+// MarshalJSON writes the Ast following the schema described by JSONVersion
+func (a *Ast) MarshalJSON() ([]byte, error) {
+	stmts := a.Statements
+	if stmts == nil {
+		stmts = []*Statement{}
+	}
+
+	return json.Marshal(astJSON{Version: JSONVersion, Statements: stmts})
+}
+
+// UnmarshalJSON reads an Ast written with the current JSONVersion
+func (a *Ast) UnmarshalJSON(data []byte) error {
+	var aj astJSON
+	if err := json.Unmarshal(data, &aj); err != nil {
+		return err
+	}
+
+	if aj.Version != JSONVersion {
+		return fmt.Errorf("Unsupported Ast JSON version %d, expected %d", aj.Version, JSONVersion)
+	}
+
+	for i, stmt := range aj.Statements {
+		if stmt == nil {
+			return fmt.Errorf("statements: Missing item %d", i)
+		}
+	}
+
+	a.Statements = aj.Statements
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (id *Identifier) MarshalJSON() ([]byte, error) {
+	return marshalNode(id)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (id *Identifier) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, id)
+}
+
+// MarshalJSON implements json.Marshaler
+func (qn *QualifiedName) MarshalJSON() ([]byte, error) {
+	return marshalNode(qn)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (qn *QualifiedName) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, qn)
+}
+
+// MarshalJSON implements json.Marshaler
+func (l *Literal) MarshalJSON() ([]byte, error) {
+	return marshalNode(l)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (l *Literal) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, l)
+}
+
+// MarshalJSON implements json.Marshaler
+func (pe *ParameterExpression) MarshalJSON() ([]byte, error) {
+	return marshalNode(pe)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (pe *ParameterExpression) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, pe)
+}
+
+// MarshalJSON implements json.Marshaler
+func (ve *ValuesExpression) MarshalJSON() ([]byte, error) {
+	return marshalNode(ve)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (ve *ValuesExpression) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, ve)
+}
+
+// MarshalJSON implements json.Marshaler
+func (in *InExpression) MarshalJSON() ([]byte, error) {
+	return marshalNode(in)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (in *InExpression) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, in)
+}
+
+// MarshalJSON implements json.Marshaler
+func (be *BinaryExpression) MarshalJSON() ([]byte, error) {
+	return marshalNode(be)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (be *BinaryExpression) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, be)
+}
+
+// MarshalJSON implements json.Marshaler
+func (tl *TypedLiteral) MarshalJSON() ([]byte, error) {
+	return marshalNode(tl)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (tl *TypedLiteral) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, tl)
+}
+
+// MarshalJSON implements json.Marshaler
+func (si *SelectItem) MarshalJSON() ([]byte, error) {
+	return marshalNode(si)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (si *SelectItem) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, si)
+}
+
+// MarshalJSON implements json.Marshaler
+func (tr *TableReference) MarshalJSON() ([]byte, error) {
+	return marshalNode(tr)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (tr *TableReference) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, tr)
+}
+
+// MarshalJSON implements json.Marshaler
+func (lc *LimitClause) MarshalJSON() ([]byte, error) {
+	return marshalNode(lc)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (lc *LimitClause) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, lc)
+}
+
+// MarshalJSON implements json.Marshaler
+func (cd *ColumnDefinition) MarshalJSON() ([]byte, error) {
+	return marshalNode(cd)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (cd *ColumnDefinition) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, cd)
+}
+
+// MarshalJSON implements json.Marshaler
+func (ie *IndexElement) MarshalJSON() ([]byte, error) {
+	return marshalNode(ie)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (ie *IndexElement) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, ie)
+}
+
+// MarshalJSON implements json.Marshaler
+func (sc *SetClause) MarshalJSON() ([]byte, error) {
+	return marshalNode(sc)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (sc *SetClause) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, sc)
+}
+
+// MarshalJSON implements json.Marshaler
+func (occ *OnConflictClause) MarshalJSON() ([]byte, error) {
+	return marshalNode(occ)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (occ *OnConflictClause) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, occ)
+}
+
+// MarshalJSON implements json.Marshaler
+func (ss *SelectStatement) MarshalJSON() ([]byte, error) {
+	return marshalNode(ss)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (ss *SelectStatement) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, ss)
+}
+
+// MarshalJSON implements json.Marshaler
+func (cts *CreateTableStatement) MarshalJSON() ([]byte, error) {
+	return marshalNode(cts)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (cts *CreateTableStatement) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, cts)
+}
+
+// MarshalJSON implements json.Marshaler
+func (cis *CreateIndexStatement) MarshalJSON() ([]byte, error) {
+	return marshalNode(cis)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (cis *CreateIndexStatement) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, cis)
+}
+
+// MarshalJSON implements json.Marshaler
+func (dis *DropIndexStatement) MarshalJSON() ([]byte, error) {
+	return marshalNode(dis)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (dis *DropIndexStatement) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, dis)
+}
+
+// MarshalJSON implements json.Marshaler
+func (dts *DropTableStatement) MarshalJSON() ([]byte, error) {
+	return marshalNode(dts)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (dts *DropTableStatement) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, dts)
+}
+
+// MarshalJSON implements json.Marshaler
+func (cvs *CreateViewStatement) MarshalJSON() ([]byte, error) {
+	return marshalNode(cvs)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (cvs *CreateViewStatement) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, cvs)
+}
+
+// MarshalJSON implements json.Marshaler
+func (rmvs *RefreshMaterializedViewStatement) MarshalJSON() ([]byte, error) {
+	return marshalNode(rmvs)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (rmvs *RefreshMaterializedViewStatement) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, rmvs)
+}
+
+// MarshalJSON implements json.Marshaler
+func (dvs *DropViewStatement) MarshalJSON() ([]byte, error) {
+	return marshalNode(dvs)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (dvs *DropViewStatement) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, dvs)
+}
+
+// MarshalJSON implements json.Marshaler
+func (is *InsertStatement) MarshalJSON() ([]byte, error) {
+	return marshalNode(is)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (is *InsertStatement) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, is)
+}
+
+// MarshalJSON implements json.Marshaler
+func (us *UpdateStatement) MarshalJSON() ([]byte, error) {
+	return marshalNode(us)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (us *UpdateStatement) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, us)
+}
+
+// MarshalJSON implements json.Marshaler
+func (ds *DeleteStatement) MarshalJSON() ([]byte, error) {
+	return marshalNode(ds)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (ds *DeleteStatement) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, ds)
+}
+
+// MarshalJSON implements json.Marshaler
+func (bs *BeginStatement) MarshalJSON() ([]byte, error) {
+	return marshalNode(bs)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (bs *BeginStatement) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, bs)
+}
+
+// MarshalJSON implements json.Marshaler
+func (cs *CommitStatement) MarshalJSON() ([]byte, error) {
+	return marshalNode(cs)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (cs *CommitStatement) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, cs)
+}
+
+// MarshalJSON implements json.Marshaler
+func (rs *RollbackStatement) MarshalJSON() ([]byte, error) {
+	return marshalNode(rs)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (rs *RollbackStatement) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, rs)
+}
+
+// MarshalJSON implements json.Marshaler
+func (ss *SavepointStatement) MarshalJSON() ([]byte, error) {
+	return marshalNode(ss)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (ss *SavepointStatement) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, ss)
+}
+
+// MarshalJSON implements json.Marshaler
+func (rss *ReleaseSavepointStatement) MarshalJSON() ([]byte, error) {
+	return marshalNode(rss)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (rss *ReleaseSavepointStatement) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, rss)
+}
@@ -0,0 +1,235 @@
+package gosqlshell
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAst_MarshalJSON(t *testing.T) {
+	parser := Parser{HelpMessagesDisabled: true}
+	ast, err := parser.Parse("SELECT 1")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	data, err := json.Marshal(ast)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"version":1,"statements":[{"node":"SelectStatement",`+
+		`"pos":{"offset":0,"line":1,"col":1},"end":{"offset":8,"line":1,"col":9},`+
+		`"items":[{"node":"SelectItem","pos":{"offset":7,"line":1,"col":8},`+
+		`"end":{"offset":8,"line":1,"col":9},"expression":{"node":"Literal",`+
+		`"pos":{"offset":7,"line":1,"col":8},"end":{"offset":8,"line":1,"col":9},`+
+		`"kind":"numeric","value":"1"}}]}]}`, string(data))
+
+	data, err = json.Marshal(&Ast{})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"version":1,"statements":[]}`, string(data))
+}
+
+func TestAst_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		source  string
+		dialect Dialect
+	}{
+		{source: "SELECT u.name AS n, * FROM public.users u WHERE id = $1 AND name <> 'it''s' LIMIT 5 OFFSET 10"},
+		{source: "SELECT id FROM users LIMIT 10, 5", dialect: MySQLDialect},
+		{source: "SELECT DATE '2024-01-01', TIMESTAMP '2024-01-01T10:00:00+02:00', TIME '10:30:15.25', INTERVAL '1 day 2 hours'"},
+		{source: "SELECT INTERVAL 3 DAY", dialect: MySQLDialect},
+		{source: "SELECT E'a\\nb', $fn$x$fn$, X'FF', B'01', N'x', NULL, true, -1.5"},
+		{source: "CREATE TABLE t (id INT PRIMARY KEY, name TEXT)"},
+		{source: "CREATE UNIQUE INDEX i ON t USING btree (a DESC NULLS LAST, (b || c)) INCLUDE (d) WHERE e = 1"},
+		{source: "DROP INDEX i; DROP TABLE t; REFRESH MATERIALIZED VIEW v; DROP MATERIALIZED VIEW v"},
+		{source: "CREATE OR REPLACE VIEW v (a) AS SELECT a FROM t"},
+		{source: "INSERT INTO t (a, b) VALUES (1, DEFAULT), (?, :b) ON CONFLICT (a) DO UPDATE SET b = 2 WHERE a = 0 RETURNING *"},
+		{source: "INSERT INTO t DEFAULT VALUES ON CONFLICT ON CONSTRAINT c DO NOTHING"},
+		{source: "INSERT INTO t SELECT a FROM s ON DUPLICATE KEY UPDATE a = 1", dialect: MySQLDialect},
+		{source: "INSERT INTO t (a, b) VALUES (1, 2) ON DUPLICATE KEY UPDATE b = VALUES(b) + 1", dialect: MySQLDialect},
+		{source: "UPDATE t SET a = 1 WHERE b = 2 RETURNING a AS x; DELETE FROM t WHERE a = 1 RETURNING a"},
+		{source: "SELECT id FROM t WHERE a IN (1, b) AND c NOT IN ('x')"},
+		{source: "BEGIN ISOLATION LEVEL SERIALIZABLE, READ ONLY; SAVEPOINT s; ROLLBACK TO SAVEPOINT s; RELEASE SAVEPOINT s; COMMIT"},
+		{source: `SELECT "Größe" FROM "Straßen"`},
+	}
+
+	for _, test := range tests {
+		parser := Parser{HelpMessagesDisabled: true, Dialect: test.dialect}
+		ast, err := parser.Parse(test.source)
+		if !assert.Nil(t, err, test.source) {
+			continue
+		}
+
+		data, err := json.Marshal(ast)
+		if !assert.Nil(t, err, test.source) {
+			continue
+		}
+
+		var decoded Ast
+		if !assert.Nil(t, json.Unmarshal(data, &decoded), test.source) {
+			continue
+		}
+
+		for _, d := range []Dialect{PostgreSQLDialect, MySQLDialect, SQLiteDialect, ANSIDialect, SQLServerDialect} {
+			expected, expectedErr := ast.GenerateCodeFor(d)
+			code, err := decoded.GenerateCodeFor(d)
+			assert.Equal(t, expected, code, test.source)
+			assert.Equal(t, expectedErr, err, test.source)
+		}
+
+		positions := func(a *Ast) []Pos {
+			ps := []Pos{}
+			Inspect(a, func(n Node) bool {
+				if n != nil {
+					ps = append(ps, n.Pos(), n.End())
+				}
+
+				return true
+			})
+
+			return ps
+		}
+		assert.Equal(t, positions(ast), positions(&decoded), test.source)
+
+		again, err := json.Marshal(&decoded)
+		assert.Nil(t, err, test.source)
+		assert.Equal(t, string(data), string(again), test.source)
+	}
+}
+
+func TestAst_UnmarshalJSON_errors(t *testing.T) {
+	tests := []struct {
+		data string
+		err  string
+	}{
+		{
+			data: `{"version":2,"statements":[]}`,
+			err:  "Unsupported Ast JSON version 2, expected 1",
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"Identifier","name":"a"}]}`,
+			err:  `Expected a statement node, got "Identifier"`,
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"DropTableStatement","name":{"node":"Identifier","name":"a"}}]}`,
+			err:  `DropTableStatement.name: Expected a QualifiedName node, got "Identifier"`,
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"DeleteStatement","where":{"node":"SelectItem"}}]}`,
+			err:  "DeleteStatement.where: SelectItem node is not an expression",
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"DeleteStatement","where":{"node":"Literal","kind":"money"}}]}`,
+			err:  `DeleteStatement.where: Literal.kind: Unknown literal kind "money"`,
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"DeleteStatement","where":{"node":"Function"}}]}`,
+			err:  `DeleteStatement.where: Unknown node "Function"`,
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"DeleteStatement","where":{"node":"ParameterExpression","placeholder":"1; DROP TABLE t"}}]}`,
+			err:  `DeleteStatement.where: ParameterExpression.placeholder: Invalid value "1; DROP TABLE t"`,
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"DeleteStatement","where":{"node":"ParameterExpression","placeholder":"$"}}]}`,
+			err:  `DeleteStatement.where: ParameterExpression.placeholder: Invalid value "$"`,
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"DeleteStatement","where":{"node":"BinaryExpression",` +
+				`"left":{"node":"Identifier","name":"a"},"right":{"node":"Identifier","name":"b"},"operator":"= b; DROP TABLE t; --"}}]}`,
+			err: `DeleteStatement.where: BinaryExpression.operator: Invalid value "= b; DROP TABLE t; --"`,
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"DeleteStatement","where":{"node":"BinaryExpression",` +
+				`"left":{"node":"Identifier","name":"a"},"operator":"="}}]}`,
+			err: `DeleteStatement.where: BinaryExpression.right: Missing operand`,
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"DeleteStatement","where":{"node":"Literal","kind":"numeric","value":"1 OR 1"}}]}`,
+			err:  `DeleteStatement.where: Literal.value: Invalid value "1 OR 1"`,
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"DeleteStatement","where":{"node":"Literal","kind":"hexString","value":"FF'"}}]}`,
+			err:  `DeleteStatement.where: Literal.value: Invalid value "FF'"`,
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"DeleteStatement","where":{"node":"Literal","kind":"bool","value":"TRUE OR 1"}}]}`,
+			err:  `DeleteStatement.where: Literal.value: Invalid value "TRUE OR 1"`,
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"DeleteStatement","where":{"node":"Literal","kind":"dollarString","tag":"a b","value":"x"}}]}`,
+			err:  `DeleteStatement.where: Literal.tag: Invalid value "a b"`,
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"DeleteStatement","where":{"node":"TypedLiteral","type":"CAST(1 AS","value":{"node":"Literal","value":"x"}}}]}`,
+			err:  `DeleteStatement.where: TypedLiteral.type: Invalid value "CAST(1 AS"`,
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"DeleteStatement","where":{"node":"TypedLiteral","type":"INTERVAL","unit":"DAY; --",` +
+				`"value":{"node":"Literal","kind":"numeric","value":"1"},"interval":{"days":1}}}]}`,
+			err: `DeleteStatement.where: TypedLiteral.unit: Invalid value "DAY; --"`,
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"DeleteStatement","where":{"node":"TypedLiteral","type":"INTERVAL","value":{"node":"Literal","value":"1 day"}}}]}`,
+			err:  `DeleteStatement.where: TypedLiteral.interval: Missing interval`,
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"DeleteStatement","where":{"node":"InExpression","expression":{"node":"Identifier","name":"a"}}}]}`,
+			err:  `DeleteStatement.where: InExpression.list: Missing expressions`,
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"CreateTableStatement","columns":[{"node":"ColumnDefinition","name":{"node":"Identifier","name":"a"},"dataType":"INT); DROP TABLE t; --"}]}]}`,
+			err:  `CreateTableStatement.columns: ColumnDefinition.dataType: Invalid value "INT); DROP TABLE t; --"`,
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"CreateIndexStatement","elements":[{"node":"IndexElement","expression":{"node":"Identifier","name":"a"},"order":"DESC)"}]}]}`,
+			err:  `CreateIndexStatement.elements: IndexElement.order: Invalid value "DESC)"`,
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"BeginStatement","accessMode":"READ ONLY; DROP TABLE t"}]}`,
+			err:  `BeginStatement.accessMode: Invalid value "READ ONLY; DROP TABLE t"`,
+		},
+		{
+			data: `{"version":1,"statements":[null]}`,
+			err:  "statements: Missing item 0",
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"SelectStatement"}]}`,
+			err:  "SelectStatement.items: Missing items",
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"SelectStatement","items":[null]}]}`,
+			err:  "SelectStatement.items: Missing item 0",
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"SelectStatement","items":[{"node":"SelectItem"}]}]}`,
+			err:  "SelectStatement.items: SelectItem.expression: Missing expression",
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"SelectStatement","items":[{"node":"SelectItem","asterisk":true}],"from":{"node":"TableReference"}}]}`,
+			err:  "SelectStatement.from: TableReference.name: Missing name",
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"InsertStatement","values":[[{"node":"Literal","kind":"numeric","value":"1"}]]}]}`,
+			err:  "InsertStatement.table: Missing table",
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"UpdateStatement","table":{"node":"QualifiedName"},` +
+				`"set":[{"node":"SetClause","column":{"node":"Identifier","name":"a"},"value":{"node":"Literal","kind":"numeric","value":"1"}}]}]}`,
+			err: "UpdateStatement.table: QualifiedName.parts: Missing name",
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"UpdateStatement","table":{"node":"QualifiedName","parts":[{"node":"Identifier","name":"t"}]},` +
+				`"set":[{"node":"SetClause","column":{"node":"Identifier","name":"a"}}]}]}`,
+			err: "UpdateStatement.set: SetClause.value: Missing value",
+		},
+		{
+			data: `{"version":1,"statements":[{"node":"CreateViewStatement","name":{"node":"QualifiedName","parts":[{"node":"Identifier","name":"v"}]}}]}`,
+			err:  "CreateViewStatement.query: Missing query",
+		},
+	}
+
+	for _, test := range tests {
+		var ast Ast
+		assert.EqualError(t, json.Unmarshal([]byte(test.data), &ast), test.err, test.data)
+	}
+}
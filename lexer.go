@@ -3,39 +3,136 @@ package gosqlshell
 import (
 	"fmt"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 type location struct {
 	line uint
 	col  uint
+	// offset in bytes, only kept for the locations of tokens
+	offset uint
 }
 
 type keyword string
 
 const (
-	selectKeyword     keyword = "select"
-	fromKeyword       keyword = "from"
-	asKeyword         keyword = "as"
-	tableKeyword      keyword = "table"
-	createKeyword     keyword = "create"
-	dropKeyword       keyword = "drop"
-	insertKeyword     keyword = "insert"
-	intoKeyword       keyword = "into"
-	valuesKeyword     keyword = "values"
-	intKeyword        keyword = "int"
-	textKeyword       keyword = "text"
-	boolKeyword       keyword = "boolean"
-	whereKeyword      keyword = "where"
-	andKeyword        keyword = "and"
-	orKeyword         keyword = "or"
-	trueKeyword       keyword = "true"
-	falseKeyword      keyword = "false"
-	primarykeyKeyword keyword = "primary key"
-	uniqueKeyword     keyword = "unique"
-	indexKeyword      keyword = "index"
-	onKeyword         keyword = "on"
+	selectKeyword       keyword = "select"
+	fromKeyword         keyword = "from"
+	asKeyword           keyword = "as"
+	tableKeyword        keyword = "table"
+	createKeyword       keyword = "create"
+	dropKeyword         keyword = "drop"
+	insertKeyword       keyword = "insert"
+	intoKeyword         keyword = "into"
+	valuesKeyword       keyword = "values"
+	inKeyword           keyword = "in"
+	notKeyword          keyword = "not"
+	intKeyword          keyword = "int"
+	textKeyword         keyword = "text"
+	boolKeyword         keyword = "boolean"
+	whereKeyword        keyword = "where"
+	andKeyword          keyword = "and"
+	orKeyword           keyword = "or"
+	trueKeyword         keyword = "true"
+	falseKeyword        keyword = "false"
+	primarykeyKeyword   keyword = "primary key"
+	uniqueKeyword       keyword = "unique"
+	indexKeyword        keyword = "index"
+	onKeyword           keyword = "on"
+	usingKeyword        keyword = "using"
+	ascKeyword          keyword = "asc"
+	descKeyword         keyword = "desc"
+	nullsKeyword        keyword = "nulls"
+	firstKeyword        keyword = "first"
+	lastKeyword         keyword = "last"
+	includeKeyword      keyword = "include"
+	viewKeyword         keyword = "view"
+	materializedKeyword keyword = "materialized"
+	refreshKeyword      keyword = "refresh"
+	replaceKeyword      keyword = "replace"
+	defaultKeyword      keyword = "default"
+	conflictKeyword     keyword = "conflict"
+	constraintKeyword   keyword = "constraint"
+	doKeyword           keyword = "do"
+	nothingKeyword      keyword = "nothing"
+	updateKeyword       keyword = "update"
+	setKeyword          keyword = "set"
+	duplicateKeyword    keyword = "duplicate"
+	keyKeyword          keyword = "key"
+	returningKeyword    keyword = "returning"
+	deleteKeyword       keyword = "delete"
+	beginKeyword        keyword = "begin"
+	startKeyword        keyword = "start"
+	transactionKeyword  keyword = "transaction"
+	isolationKeyword    keyword = "isolation"
+	levelKeyword        keyword = "level"
+	readKeyword         keyword = "read"
+	onlyKeyword         keyword = "only"
+	writeKeyword        keyword = "write"
+	commitKeyword       keyword = "commit"
+	rollbackKeyword     keyword = "rollback"
+	toKeyword           keyword = "to"
+	savepointKeyword    keyword = "savepoint"
+	releaseKeyword      keyword = "release"
+	serializableKeyword keyword = "serializable"
+	repeatableKeyword   keyword = "repeatable"
+	committedKeyword    keyword = "committed"
+	uncommittedKeyword  keyword = "uncommitted"
+	nullKeyword         keyword = "null"
+	dateKeyword         keyword = "date"
+	timeKeyword         keyword = "time"
+	timestampKeyword    keyword = "timestamp"
+	intervalKeyword     keyword = "interval"
+	limitKeyword        keyword = "limit"
+	offsetKeyword       keyword = "offset"
+	fetchKeyword        keyword = "fetch"
+	nextKeyword         keyword = "next"
+	rowKeyword          keyword = "row"
+	rowsKeyword         keyword = "rows"
+	serialKeyword       keyword = "serial"
+	integerKeyword      keyword = "integer"
+	generatedKeyword    keyword = "generated"
+	byKeyword           keyword = "by"
+	identityKeyword     keyword = "identity"
+	// MySQL and SQLite spell auto increment differently
+	autoIncrementKeyword keyword = "auto_increment"
+	autoincrementKeyword keyword = "autoincrement"
 )
 
+// reservedKeywords can never be used as identifiers or aliases, the
+// remaining keywords are only special in the statements that use them
+var reservedKeywords = map[keyword]bool{
+	selectKeyword:     true,
+	fromKeyword:       true,
+	asKeyword:         true,
+	tableKeyword:      true,
+	createKeyword:     true,
+	intoKeyword:       true,
+	whereKeyword:      true,
+	andKeyword:        true,
+	orKeyword:         true,
+	trueKeyword:       true,
+	falseKeyword:      true,
+	primarykeyKeyword: true,
+	uniqueKeyword:     true,
+	onKeyword:         true,
+	defaultKeyword:    true,
+	constraintKeyword: true,
+	doKeyword:         true,
+	returningKeyword:  true,
+	toKeyword:         true,
+	onlyKeyword:       true,
+	nullKeyword:       true,
+	limitKeyword:      true,
+	offsetKeyword:     true,
+	fetchKeyword:      true,
+}
+
+func isReservedKeyword(k keyword) bool {
+	return reservedKeywords[k]
+}
+
 type symbol string
 
 const (
@@ -48,6 +145,8 @@ const (
 	neqSymbol        symbol = "<>"
 	concatSymbol     symbol = "||"
 	plusSymbol       symbol = "+"
+	minusSymbol      symbol = "-"
+	dotSymbol        symbol = "."
 )
 
 type tokenKind uint
@@ -56,36 +155,58 @@ const (
 	keywordKind tokenKind = iota
 	symbolKind
 	identifierKind
+	quotedIdentifierKind
 	stringKind
 	numericKind
 	boolKind
+	commentKind
+	parameterKind
+	escapeStringKind
+	dollarStringKind
+	hexStringKind
+	bitStringKind
+	nationalStringKind
 )
 
 type token struct {
 	value string
 	kind  tokenKind
 	loc   location
+	// end is the location right after the token, set by lexWithOptions
+	end location
+	// tag of dollar quoted strings, e.g. fn for $fn$...$fn$
+	tag string
+	// comments surrounding the token, only kept when requested
+	leading  []string
+	trailing []string
+	// blankLine is set when a blank line comes before the token or one of
+	// its leading comments, only when comments are kept
+	blankLine bool
 }
 
 func (t token) bindingPower() uint {
 	switch t.kind {
 	case keywordKind:
 		switch keyword(t.value) {
-		case andKeyword:
-			fallthrough
 		case orKeyword:
 			return 1
+		case andKeyword:
+			return 2
+		case inKeyword:
+			return 3
 		}
 	case symbolKind:
 		switch symbol(t.value) {
 		case eqSymbol:
 			fallthrough
 		case neqSymbol:
-			fallthrough
+			return 3
 		case concatSymbol:
 			fallthrough
 		case plusSymbol:
-			return 3
+			fallthrough
+		case minusSymbol:
+			return 4
 		}
 	}
 
@@ -111,7 +232,13 @@ func longestMatch(source string, ic cursor, options []string) string {
 	cur := ic
 
 	for cur.pointer < uint(len(source)) {
-		value = append(value, strings.ToLower(string(source[cur.pointer]))...)
+		// keywords and symbols are all ASCII, so any other rune ends the match
+		r := source[cur.pointer]
+		if r >= utf8.RuneSelf {
+			break
+		}
+
+		value = append(value, byte(unicode.ToLower(rune(r))))
 		cur.pointer++
 
 	match:
@@ -132,9 +259,8 @@ func longestMatch(source string, ic cursor, options []string) string {
 				continue
 			}
 
-			sharesPrefix := string(value) == option[:cur.pointer-ic.pointer]
 			tooLong := len(value) > len(option)
-			if tooLong || !sharesPrefix {
+			if tooLong || string(value) != option[:len(value)] {
 				skipList = append(skipList, i)
 			}
 		}
@@ -146,23 +272,25 @@ func longestMatch(source string, ic cursor, options []string) string {
 
 	return match
 }
-
 func lexSymbol(source string, ic cursor) (*token, cursor, bool) {
-	c := source[ic.pointer]
+	c, size := utf8.DecodeRuneInString(source[ic.pointer:])
 	cur := ic
 	// will get overwritten later if not an ignored syntax
-	cur.pointer++
+	cur.pointer += uint(size)
 	cur.loc.col++
 
-	switch c {
-	case '\n':
+	switch {
+	case c == '\n':
 		cur.loc.line++
 		cur.loc.col = 0
-		fallthrough
-	case '\t':
-		fallthrough
-	case ' ':
 		return nil, cur, true
+	case unicode.IsSpace(c):
+		return nil, cur, true
+	case c == '.':
+		// leave numbers like .5 to the numeric lexer
+		if ic.pointer+1 < uint(len(source)) && source[ic.pointer+1] >= '0' && source[ic.pointer+1] <= '9' {
+			return nil, ic, false
+		}
 	}
 
 	symbol := []symbol{
@@ -170,11 +298,13 @@ func lexSymbol(source string, ic cursor) (*token, cursor, bool) {
 		neqSymbol,
 		concatSymbol,
 		plusSymbol,
+		minusSymbol,
 		commaSymbol,
 		leftParenSymbol,
 		rightParenSymbol,
 		semicolonSymbol,
 		asteriskSymbol,
+		dotSymbol,
 	}
 
 	var options []string
@@ -198,31 +328,107 @@ func lexSymbol(source string, ic cursor) (*token, cursor, bool) {
 
 }
 
-func lexKeyword(source string, ic cursor) (*token, cursor, bool) {
-	cur := ic
-	keywords := []keyword{
-		selectKeyword,
-		insertKeyword,
-		valuesKeyword,
-		tableKeyword,
-		createKeyword,
-		dropKeyword,
-		whereKeyword,
-		fromKeyword,
-		intoKeyword,
-		textKeyword,
-		boolKeyword,
-		intKeyword,
-		andKeyword,
-		orKeyword,
-		asKeyword,
-		trueKeyword,
-		falseKeyword,
+var keywords = []keyword{
+	selectKeyword,
+	insertKeyword,
+	valuesKeyword,
+	inKeyword,
+	notKeyword,
+	tableKeyword,
+	createKeyword,
+	dropKeyword,
+	whereKeyword,
+	fromKeyword,
+	intoKeyword,
+	textKeyword,
+	boolKeyword,
+	intKeyword,
+	andKeyword,
+	orKeyword,
+	asKeyword,
+	trueKeyword,
+	falseKeyword,
+	primarykeyKeyword,
+	uniqueKeyword,
+	indexKeyword,
+	onKeyword,
+	usingKeyword,
+	ascKeyword,
+	descKeyword,
+	nullsKeyword,
+	firstKeyword,
+	lastKeyword,
+	includeKeyword,
+	viewKeyword,
+	materializedKeyword,
+	refreshKeyword,
+	replaceKeyword,
+	defaultKeyword,
+	conflictKeyword,
+	constraintKeyword,
+	doKeyword,
+	nothingKeyword,
+	updateKeyword,
+	setKeyword,
+	duplicateKeyword,
+	keyKeyword,
+	returningKeyword,
+	deleteKeyword,
+	beginKeyword,
+	startKeyword,
+	transactionKeyword,
+	isolationKeyword,
+	levelKeyword,
+	readKeyword,
+	onlyKeyword,
+	writeKeyword,
+	commitKeyword,
+	rollbackKeyword,
+	toKeyword,
+	savepointKeyword,
+	releaseKeyword,
+	serializableKeyword,
+	repeatableKeyword,
+	committedKeyword,
+	uncommittedKeyword,
+	nullKeyword,
+	dateKeyword,
+	timeKeyword,
+	timestampKeyword,
+	intervalKeyword,
+	limitKeyword,
+	offsetKeyword,
+	fetchKeyword,
+	nextKeyword,
+	rowKeyword,
+	rowsKeyword,
+	serialKeyword,
+	integerKeyword,
+	generatedKeyword,
+	byKeyword,
+	identityKeyword,
+	autoIncrementKeyword,
+	autoincrementKeyword,
+}
+
+func isKeyword(s string) bool {
+	for _, k := range keywords {
+		if string(k) == s {
+			return true
+		}
 	}
 
+	return false
+}
+
+func (d Dialect) lexKeyword(source string, ic cursor) (*token, cursor, bool) {
+	cur := ic
+
 	var options []string
 	for _, k := range keywords {
-		options = append(options, string(k))
+		if d.hasKeyword(k) {
+			options = append(options, string(k))
+		}
 	}
 
 	match := longestMatch(source, ic, options)
@@ -230,7 +436,14 @@ func lexKeyword(source string, ic cursor) (*token, cursor, bool) {
 		return nil, ic, false
 	}
 
-	cur.pointer = ic.pointer + uint(len(match))
+	// keywords must end on a word boundary, otherwise "orders" would
+	// lex as the keyword "or" followed by the identifier "ders"
+	end := ic.pointer + uint(len(match))
+	if r, _ := utf8.DecodeRuneInString(source[end:]); end < uint(len(source)) && isIdentifierPart(r) {
+		return nil, ic, false
+	}
+
+	cur.pointer = end
 	cur.loc.col = ic.loc.col + uint(len(match))
 
 	kind := keywordKind
@@ -253,7 +466,6 @@ func lexNumeric(source string, ic cursor) (*token, cursor, bool) {
 
 	for ; cur.pointer < uint(len(source)); cur.pointer++ {
 		c := source[cur.pointer]
-		cur.loc.col++
 
 		isDigit := c >= '0' && c <= '9'
 		isPeriod := c == '.'
@@ -266,6 +478,7 @@ func lexNumeric(source string, ic cursor) (*token, cursor, bool) {
 			}
 
 			periodFound = isPeriod
+			cur.loc.col++
 			continue
 		}
 
@@ -275,6 +488,7 @@ func lexNumeric(source string, ic cursor) (*token, cursor, bool) {
 			}
 
 			periodFound = true
+			cur.loc.col++
 			continue
 		}
 
@@ -298,12 +512,15 @@ func lexNumeric(source string, ic cursor) (*token, cursor, bool) {
 				cur.loc.col++
 			}
 
+			cur.loc.col++
 			continue
 		}
 
 		if !isDigit {
 			break
 		}
+
+		cur.loc.col++
 	}
 
 	// no characters accumulated
@@ -317,15 +534,21 @@ func lexNumeric(source string, ic cursor) (*token, cursor, bool) {
 		kind:  numericKind,
 	}, cur, true
 }
-
 func lexCharacterDelimited(source string, ic cursor, delimiter byte) (*token, cursor, bool) {
+	return lexDelimited(source, ic, delimiter, delimiter, false)
+}
+
+// lexDelimited lexes text between delimiters, keeping escapes as written.
+// Doubled closing delimiters are always an escape, backslashes only when
+// asked.
+func lexDelimited(source string, ic cursor, open, delimiter byte, backslashEscapes bool) (*token, cursor, bool) {
 	cur := ic
 
 	if len(source[cur.pointer:]) == 0 {
 		return nil, ic, false
 	}
 
-	if source[cur.pointer] != delimiter {
+	if source[cur.pointer] != open {
 		return nil, ic, false
 	}
 
@@ -333,10 +556,10 @@ func lexCharacterDelimited(source string, ic cursor, delimiter byte) (*token, cu
 	cur.pointer++
 
 	var value []byte
-	for ; cur.pointer < uint(len(source)); cur.pointer++ {
-		c := source[cur.pointer]
+	for cur.pointer < uint(len(source)) {
+		c, size := utf8.DecodeRuneInString(source[cur.pointer:])
 
-		if c == delimiter {
+		if c == rune(delimiter) {
 			// SQL escapes are via double characters, not backslash
 			if cur.pointer+1 >= uint(len(source)) || source[cur.pointer+1] != delimiter {
 				cur.pointer++
@@ -351,80 +574,438 @@ func lexCharacterDelimited(source string, ic cursor, delimiter byte) (*token, cu
 			value = append(value, delimiter)
 			cur.pointer++
 			cur.loc.col++
+		} else if c == '\\' && backslashEscapes && cur.pointer+1 < uint(len(source)) {
+			value = append(value, '\\')
+			cur.pointer++
+			cur.loc.col++
+			c, size = utf8.DecodeRuneInString(source[cur.pointer:])
 		}
 
-		value = append(value, c)
+		value = append(value, source[cur.pointer:cur.pointer+uint(size)]...)
+		cur.pointer += uint(size)
 		cur.loc.col++
+
+		if c == '\n' {
+			cur.loc.line++
+			cur.loc.col = 0
+		}
 	}
 
 	return nil, ic, false
 }
+func isIdentifierStart(c rune) bool {
+	return unicode.IsLetter(c)
+}
 
-func lexIdentifier(source string, ic cursor) (*token, cursor, bool) {
-	// handle separately if is a double quoted identifier
-	if token, newCursor, ok := lexCharacterDelimited(source, ic, '"'); ok {
+func isIdentifierPart(c rune) bool {
+	// combining marks keep decomposed accents like e\u0301 in one identifier
+	isMark := unicode.In(c, unicode.Mn, unicode.Mc)
+	return isIdentifierStart(c) || isMark || unicode.IsDigit(c) || c == '$' || c == '_'
+}
+
+// foldIdentifier case folds unquoted identifiers. Lowering the upper case
+// form also unifies runes with several lower case forms, such as the Greek
+// final sigma.
+func foldIdentifier(s string) string {
+	return strings.Map(func(r rune) rune {
+		return unicode.ToLower(unicode.ToUpper(r))
+	}, s)
+}
+
+func (d Dialect) lexIdentifier(source string, ic cursor) (*token, cursor, bool) {
+	// handle separately if is a quoted identifier, which keeps its case and
+	// unescapes doubled quotes, e.g. "" into "
+	for open, close := range d.identifierQuotes() {
+		if token, newCursor, ok := lexDelimited(source, ic, open, close, false); ok {
+			token.value = strings.ReplaceAll(token.value, string([]byte{close, close}), string(close))
+			token.kind = quotedIdentifierKind
+			return token, newCursor, true
+		}
+	}
+
+	cur := ic
+
+	c, size := utf8.DecodeRuneInString(source[cur.pointer:])
+	if !isIdentifierStart(c) {
+		return nil, ic, false
+	}
+	cur.pointer += uint(size)
+	cur.loc.col++
+
+	for cur.pointer < uint(len(source)) {
+		c, size = utf8.DecodeRuneInString(source[cur.pointer:])
+		if !isIdentifierPart(c) {
+			break
+		}
+
+		cur.pointer += uint(size)
+		cur.loc.col++
+	}
+
+	return &token{
+		// unique identifiers are case insensitive
+		value: foldIdentifier(source[ic.pointer:cur.pointer]),
+		loc:   ic.loc,
+		kind:  identifierKind,
+	}, cur, true
+}
+func (d Dialect) lexString(source string, ic cursor) (*token, cursor, bool) {
+	if d.hasStringPrefix('$') {
+		if token, newCursor, ok := lexDollarQuoted(source, ic); ok {
+			return token, newCursor, true
+		}
+	}
+
+	if token, newCursor, ok := d.lexPrefixedString(source, ic); ok {
 		return token, newCursor, true
 	}
 
+	for _, quote := range d.stringQuotes() {
+		token, newCursor, ok := lexDelimited(source, ic, quote, quote, d.backslashEscapes())
+		if ok {
+			// string tokens hold the body of a standard single quoted string
+			token.value = standardString(token.value, quote, d.backslashEscapes())
+			return token, newCursor, true
+		}
+	}
+
+	return nil, ic, false
+}
+
+// standardString converts the escaped body of a string delimited by quote
+// into the body of a standard single quoted string, where the only escape
+// is a doubled quote. MySQL's backslash escapes are resolved on the way.
+func standardString(body string, quote byte, backslashEscapes bool) string {
+	if quote == '\'' && !backslashEscapes {
+		return body
+	}
+
+	backslashed := map[byte]string{
+		'0': "\x00",
+		'b': "\b",
+		'n': "\n",
+		'r': "\r",
+		't': "\t",
+		'Z': "\x1a",
+		// kept escaped as they are LIKE wildcards otherwise
+		'%': "\\%",
+		'_': "\\_",
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case backslashEscapes && c == '\\' && i+1 < len(body):
+			i++
+			if v, ok := backslashed[body[i]]; ok {
+				b.WriteString(v)
+			} else {
+				b.WriteByte(body[i])
+			}
+		case c == quote && i+1 < len(body) && body[i+1] == quote:
+			b.WriteByte(c)
+			i++
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return strings.ReplaceAll(b.String(), "'", "''")
+}
+
+// lexPrefixedString lexes E'...' escape strings, X'...' hex strings, B'...'
+// bit strings and N'...' national strings
+func (d Dialect) lexPrefixedString(source string, ic cursor) (*token, cursor, bool) {
+	cur := ic
+
+	if cur.pointer >= uint(len(source)) {
+		return nil, ic, false
+	}
+
+	prefix := byte(unicode.ToUpper(rune(source[cur.pointer])))
+	if !d.hasStringPrefix(prefix) {
+		return nil, ic, false
+	}
+
+	var kind tokenKind
+	switch prefix {
+	case 'E':
+		kind = escapeStringKind
+	case 'X':
+		kind = hexStringKind
+	case 'B':
+		kind = bitStringKind
+	case 'N':
+		kind = nationalStringKind
+	default:
+		return nil, ic, false
+	}
+
+	cur.pointer++
+	cur.loc.col++
+
+	backslashEscapes := kind == escapeStringKind || kind == nationalStringKind && d.backslashEscapes()
+	token, cur, ok := lexDelimited(source, cur, '\'', '\'', backslashEscapes)
+	if !ok {
+		return nil, ic, false
+	}
+
+	digits := ""
+	switch kind {
+	case hexStringKind:
+		digits = "0123456789abcdefABCDEF"
+	case bitStringKind:
+		digits = "01"
+	}
+
+	if digits != "" && strings.Trim(token.value, digits) != "" {
+		return nil, ic, false
+	}
+
+	if kind == nationalStringKind {
+		token.value = standardString(token.value, '\'', backslashEscapes)
+	}
+
+	token.kind = kind
+	token.loc = ic.loc
+	return token, cur, true
+}
+
+// lexDollarQuoted lexes PostgreSQL dollar quoted strings like $$it's$$ or
+// $fn$...$fn$, whose body is kept verbatim
+func lexDollarQuoted(source string, ic cursor) (*token, cursor, bool) {
 	cur := ic
 
-	c := source[cur.pointer]
-	// other characters count too, big ignoring non ascii for now
-	isAlphabetical := (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
-	if !isAlphabetical {
+	if cur.pointer >= uint(len(source)) {
+		return nil, ic, false
+	}
+
+	if source[cur.pointer] != '$' {
 		return nil, ic, false
 	}
+
+	// the tag follows identifier rules but can't hold a $, which also keeps
+	// positional parameters like $1 apart
+	end := strings.IndexByte(source[cur.pointer+1:], '$')
+	if end == -1 {
+		return nil, ic, false
+	}
+
+	tag := source[cur.pointer+1 : cur.pointer+1+uint(end)]
+	for i, c := range tag {
+		if i == 0 && !isIdentifierStart(c) && c != '_' || !isIdentifierPart(c) || c == '$' {
+			return nil, ic, false
+		}
+	}
+
+	delimiter := "$" + tag + "$"
+	bodyStart := cur.pointer + uint(len(delimiter))
+	bodyEnd := strings.Index(source[bodyStart:], delimiter)
+	if bodyEnd == -1 {
+		return nil, ic, false
+	}
+
+	body := source[bodyStart : bodyStart+uint(bodyEnd)]
+	cur = advance(cur, delimiter+body+delimiter)
+
+	return &token{
+		value: body,
+		kind:  dollarStringKind,
+		tag:   tag,
+		loc:   ic.loc,
+	}, cur, true
+}
+
+// advance moves the cursor past text, tracking lines and columns
+func advance(ic cursor, text string) cursor {
+	cur := ic
+	for _, c := range text {
+		cur.loc.col++
+		if c == '\n' {
+			cur.loc.line++
+			cur.loc.col = 0
+		}
+	}
+
+	cur.pointer += uint(len(text))
+	return cur
+}
+
+// lexParameter lexes bind parameter placeholders, either positional ($1, ?)
+// or named (:id, @id)
+func lexParameter(source string, ic cursor) (*token, cursor, bool) {
+	cur := ic
+
+	prefix := source[cur.pointer]
+	if prefix == '?' {
+		cur.pointer++
+		cur.loc.col++
+
+		return &token{
+			value: "?",
+			loc:   ic.loc,
+			kind:  parameterKind,
+		}, cur, true
+	}
+
+	if prefix != '$' && prefix != ':' && prefix != '@' {
+		return nil, ic, false
+	}
+
 	cur.pointer++
 	cur.loc.col++
 
-	value := []byte{c}
-	for ; cur.pointer < uint(len(source)); cur.pointer++ {
-		c = source[cur.pointer]
+	for cur.pointer < uint(len(source)) {
+		c, size := utf8.DecodeRuneInString(source[cur.pointer:])
 
-		// other characters count too, big ignoring non ascii for now
-		isAlphabetical := (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
-		isNumeric := c >= '0' && c <= '9'
-		if isAlphabetical || isNumeric || c == '$' || c == '_' {
-			value = append(value, c)
-			cur.loc.col++
-			continue
+		if prefix == '$' {
+			if c < '0' || c > '9' {
+				break
+			}
+		} else if cur.pointer == ic.pointer+1 {
+			// names must start with a letter, like other identifiers
+			if !isIdentifierStart(c) {
+				break
+			}
+		} else if !isIdentifierPart(c) || c == '$' {
+			break
 		}
 
-		break
+		cur.pointer += uint(size)
+		cur.loc.col++
 	}
 
-	if len(value) == 0 {
+	if cur.pointer == ic.pointer+1 {
 		return nil, ic, false
 	}
 
 	return &token{
-		// unique identifiers are case insensitive
-		value: strings.ToLower(string(value)),
+		value: source[ic.pointer:cur.pointer],
 		loc:   ic.loc,
-		kind:  identifierKind,
+		kind:  parameterKind,
 	}, cur, true
 }
+func lexComment(source string, ic cursor) (*token, cursor, bool) {
+	cur := ic
+
+	if strings.HasPrefix(source[cur.pointer:], "--") {
+		end := strings.IndexByte(source[cur.pointer:], '\n')
+		if end == -1 {
+			end = len(source[cur.pointer:])
+		}
+
+		cur.pointer += uint(end)
+		cur.loc.col += uint(utf8.RuneCountInString(source[ic.pointer:cur.pointer]))
+
+		return &token{
+			value: source[ic.pointer:cur.pointer],
+			loc:   ic.loc,
+			kind:  commentKind,
+		}, cur, true
+	}
 
-func lexString(source string, ic cursor) (*token, cursor, bool) {
-	return lexCharacterDelimited(source, ic, '\'')
+	if !strings.HasPrefix(source[cur.pointer:], "/*") {
+		return nil, ic, false
+	}
+
+	// block comments nest, unlike in C
+	depth := 0
+	for cur.pointer < uint(len(source)) {
+		switch {
+		case strings.HasPrefix(source[cur.pointer:], "/*"):
+			depth++
+			cur.pointer += 2
+			cur.loc.col += 2
+		case strings.HasPrefix(source[cur.pointer:], "*/"):
+			depth--
+			cur.pointer += 2
+			cur.loc.col += 2
+
+			if depth == 0 {
+				return &token{
+					value: source[ic.pointer:cur.pointer],
+					loc:   ic.loc,
+					kind:  commentKind,
+				}, cur, true
+			}
+		case source[cur.pointer] == '\n':
+			cur.pointer++
+			cur.loc.line++
+			cur.loc.col = 0
+		default:
+			_, size := utf8.DecodeRuneInString(source[cur.pointer:])
+			cur.pointer += uint(size)
+			cur.loc.col++
+		}
+	}
+
+	return nil, ic, false
+}
+
+type lexOptions struct {
+	// keepComments attaches comments to the surrounding tokens as leading
+	// or trailing trivia instead of dropping them
+	keepComments bool
+	dialect      Dialect
 }
 
 func lex(source string) ([]*token, error) {
+	return lexWithOptions(source, lexOptions{})
+}
+
+func lexWithOptions(source string, opts lexOptions) ([]*token, error) {
 	tokens := []*token{}
 	cur := cursor{}
 
+	var comments []string
+	// end of the previous token or comment, to find blank lines
+	prevEnd := uint(0)
+	blankLine := false
+
 lex:
 	for cur.pointer < uint(len(source)) {
-		lexers := []lexer{lexKeyword, lexSymbol, lexString, lexNumeric, lexIdentifier}
+		d := opts.dialect
+		lexers := []lexer{lexComment, d.lexKeyword, lexSymbol, d.lexString, lexParameter, lexNumeric, d.lexIdentifier}
 		for _, l := range lexers {
 			if token, newCursor, ok := l(source, cur); ok {
+				start := cur.pointer
 				cur = newCursor
 
 				// Omit nil tokens for valid, but empty syntax like newlines
-				if token != nil {
-					tokens = append(tokens, token)
+				if token == nil {
+					continue lex
+				}
+
+				gap := source[prevEnd:start]
+				prevEnd = cur.pointer
+				if token.kind == commentKind {
+					if !opts.keepComments {
+						continue lex
+					}
+
+					// a comment on the same line as the previous token trails it,
+					// anything else leads the next token
+					if len(tokens) > 0 && len(comments) == 0 && tokens[len(tokens)-1].loc.line == token.loc.line {
+						last := tokens[len(tokens)-1]
+						last.trailing = append(last.trailing, token.value)
+					} else {
+						comments = append(comments, token.value)
+						blankLine = blankLine || strings.Count(gap, "\n") > 1
+					}
+
+					continue lex
 				}
 
+				token.loc.offset = start
+				token.end = cur.loc
+				token.end.offset = cur.pointer
+				token.leading = comments
+				token.blankLine = opts.keepComments && (blankLine || strings.Count(gap, "\n") > 1)
+				comments = nil
+				blankLine = false
+				tokens = append(tokens, token)
+
 				continue lex
 			}
 		}
@@ -433,7 +1014,13 @@ lex:
 		if len(tokens) > 0 {
 			hint = " after " + tokens[len(tokens)-1].value
 		}
-		return nil, fmt.Errorf("Unable to lex token%s, at %d:%d", hint, cur.loc.line, cur.loc.col)
+		return nil, fmt.Errorf("Unable to lex token%s, at %s", hint, cur.loc.pos())
+	}
+
+	// comments after the last token have nothing left to lead
+	if len(comments) > 0 && len(tokens) > 0 {
+		last := tokens[len(tokens)-1]
+		last.trailing = append(last.trailing, comments...)
 	}
 
 	return tokens, nil
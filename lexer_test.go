@@ -130,7 +130,7 @@ func TestToken_lexString(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		tok, _, ok := lexString(test.value, cursor{})
+		tok, _, ok := PostgreSQLDialect.lexString(test.value, cursor{})
 		assert.Equal(t, test.string, ok, test.value)
 		if ok {
 			test.value = strings.TrimSpace(test.value)
@@ -139,6 +139,45 @@ func TestToken_lexString(t *testing.T) {
 	}
 }
 
+func TestToken_lexExtendedString(t *testing.T) {
+	tests := []struct {
+		input string
+		ok    bool
+		kind  tokenKind
+		value string
+		tag   string
+		end   location
+	}{
+		{input: `E'a\nb'`, ok: true, kind: escapeStringKind, value: `a\nb`, end: location{col: 7}},
+		{input: `e'it\'s'`, ok: true, kind: escapeStringKind, value: `it\'s`, end: location{col: 8}},
+		{input: `E'it''s'`, ok: true, kind: escapeStringKind, value: `it''s`, end: location{col: 8}},
+		{input: "$$it's$$", ok: true, kind: dollarStringKind, value: "it's", end: location{col: 8}},
+		{input: "$fn$a $$ b\nc$fn$ x", ok: true, kind: dollarStringKind, value: "a $$ b\nc", tag: "fn", end: location{line: 1, col: 5}},
+		{input: "X'DEADbeef'", ok: true, kind: hexStringKind, value: "DEADbeef", end: location{col: 11}},
+		{input: "b'1010'", ok: true, kind: bitStringKind, value: "1010", end: location{col: 7}},
+		{input: "N'Zoë'", ok: true, kind: nationalStringKind, value: "Zoë", end: location{col: 6}},
+		// false tests
+		{input: "$1"},
+		{input: "$1$a$1$"},
+		{input: "$tag$unterminated"},
+		{input: "X'XYZ'"},
+		{input: "B'102'"},
+		{input: `E'\'`},
+		{input: "N"},
+	}
+
+	for _, test := range tests {
+		tok, cur, ok := PostgreSQLDialect.lexString(test.input, cursor{})
+		assert.Equal(t, test.ok, ok, test.input)
+		if ok {
+			assert.Equal(t, test.kind, tok.kind, test.input)
+			assert.Equal(t, test.value, tok.value, test.input)
+			assert.Equal(t, test.tag, tok.tag, test.input)
+			assert.Equal(t, test.end, cur.loc, test.input)
+		}
+	}
+}
+
 func TestToken_lexSymbol(t *testing.T) {
 	tests := []struct {
 		symbol bool
@@ -152,6 +191,15 @@ func TestToken_lexSymbol(t *testing.T) {
 			symbol: true,
 			value:  "||",
 		},
+		{
+			symbol: true,
+			value:  ".",
+		},
+		// false tests
+		{
+			symbol: false,
+			value:  ".5",
+		},
 	}
 
 	for _, test := range tests {
@@ -205,11 +253,55 @@ func TestToken_lexIdentifier(t *testing.T) {
 			input:      `"userName"`,
 			value:      "userName",
 		},
+		{
+			identifier: true,
+			input:      `"say ""hi"""`,
+			value:      `say "hi"`,
+		},
+		{
+			identifier: true,
+			input:      `"UserId"`,
+			value:      "UserId",
+		},
+		{
+			identifier: true,
+			input:      "café",
+			value:      "café",
+		},
+		{
+			identifier: true,
+			input:      "Ñandú_1 ",
+			value:      "ñandú_1",
+		},
+		{
+			identifier: true,
+			input:      "用户表",
+			value:      "用户表",
+		},
+		{
+			identifier: true,
+			input:      "cafe\u0301",
+			value:      "cafe\u0301",
+		},
+		{
+			identifier: true,
+			input:      "ΟΔΟΣ",
+			value:      "οδοσ",
+		},
+		{
+			identifier: true,
+			input:      "οδος",
+			value:      "οδοσ",
+		},
 		// false tests
 		{
 			identifier: false,
 			input:      `"`,
 		},
+		{
+			identifier: false,
+			input:      "١٢",
+		},
 		{
 			identifier: false,
 			input:      "_sadsfa",
@@ -225,10 +317,15 @@ func TestToken_lexIdentifier(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		tok, _, ok := lexIdentifier(test.input, cursor{})
+		tok, _, ok := PostgreSQLDialect.lexIdentifier(test.input, cursor{})
 		assert.Equal(t, test.identifier, ok, test.input)
 		if ok {
 			assert.Equal(t, test.value, tok.value, test.input)
+			kind := identifierKind
+			if test.input[0] == '"' {
+				kind = quotedIdentifierKind
+			}
+			assert.Equal(t, kind, tok.kind, test.input)
 		}
 	}
 }
@@ -258,11 +355,31 @@ func TestToken_lexKeyword(t *testing.T) {
 			keyword: true,
 			value:   "into",
 		},
+		{
+			keyword: true,
+			value:   "primary key",
+		},
+		{
+			keyword: true,
+			value:   "desc",
+		},
 		// false tests
 		{
 			keyword: false,
 			value:   " into",
 		},
+		{
+			keyword: false,
+			value:   "orders",
+		},
+		{
+			keyword: false,
+			value:   "description",
+		},
+		{
+			keyword: false,
+			value:   "selecté",
+		},
 		{
 			keyword: false,
 			value:   "flubbrety",
@@ -270,7 +387,7 @@ func TestToken_lexKeyword(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		tok, _, ok := lexKeyword(test.value, cursor{})
+		tok, _, ok := PostgreSQLDialect.lexKeyword(test.value, cursor{})
 		assert.Equal(t, test.keyword, ok, test.value)
 		if ok {
 			test.value = strings.TrimSpace(test.value)
@@ -279,6 +396,215 @@ func TestToken_lexKeyword(t *testing.T) {
 	}
 }
 
+func TestToken_lexParameter(t *testing.T) {
+	tests := []struct {
+		parameter bool
+		input     string
+		value     string
+	}{
+		{
+			parameter: true,
+			input:     "$1",
+			value:     "$1",
+		},
+		{
+			parameter: true,
+			input:     "$12)",
+			value:     "$12",
+		},
+		{
+			parameter: true,
+			input:     "?,",
+			value:     "?",
+		},
+		{
+			parameter: true,
+			input:     ":user_id ",
+			value:     ":user_id",
+		},
+		{
+			parameter: true,
+			input:     "@userId",
+			value:     "@userId",
+		},
+		// false tests
+		{
+			parameter: false,
+			input:     "$a",
+		},
+		{
+			parameter: false,
+			input:     ":1",
+		},
+		{
+			parameter: false,
+			input:     "@ id",
+		},
+		{
+			parameter: false,
+			input:     "id",
+		},
+	}
+
+	for _, test := range tests {
+		tok, _, ok := lexParameter(test.input, cursor{})
+		assert.Equal(t, test.parameter, ok, test.input)
+		if ok {
+			assert.Equal(t, test.value, tok.value, test.input)
+			assert.Equal(t, parameterKind, tok.kind, test.input)
+		}
+	}
+}
+
+func TestToken_lexComment(t *testing.T) {
+	tests := []struct {
+		comment bool
+		input   string
+		value   string
+	}{
+		{
+			comment: true,
+			input:   "-- foo",
+			value:   "-- foo",
+		},
+		{
+			comment: true,
+			input:   "-- foo\nselect",
+			value:   "-- foo",
+		},
+		{
+			comment: true,
+			input:   "/* foo */ select",
+			value:   "/* foo */",
+		},
+		{
+			comment: true,
+			input:   "/* a /* nested */ comment */",
+			value:   "/* a /* nested */ comment */",
+		},
+		{
+			comment: true,
+			input:   "/* multi\nline */",
+			value:   "/* multi\nline */",
+		},
+		// false tests
+		{
+			comment: false,
+			input:   "- foo",
+		},
+		{
+			comment: false,
+			input:   "/* unterminated",
+		},
+		{
+			comment: false,
+			input:   "/* a /* nested */",
+		},
+	}
+
+	for _, test := range tests {
+		tok, _, ok := lexComment(test.input, cursor{})
+		assert.Equal(t, test.comment, ok, test.input)
+		if ok {
+			assert.Equal(t, test.value, tok.value, test.input)
+			assert.Equal(t, commentKind, tok.kind, test.input)
+		}
+	}
+}
+
+func TestLexWithOptions_keepComments(t *testing.T) {
+	source := `-- fetch users
+SELECT id, -- the key
+/* from */ name
+FROM users; /* done */`
+
+	tokens, err := lexWithOptions(source, lexOptions{keepComments: true})
+	assert.Nil(t, err)
+	if assert.Len(t, tokens, 7) {
+		assert.Equal(t, []string{"-- fetch users"}, tokens[0].leading)
+		assert.Equal(t, []string{"-- the key"}, tokens[2].trailing)
+		assert.Equal(t, []string{"/* from */"}, tokens[3].leading)
+		assert.Equal(t, []string{"/* done */"}, tokens[6].trailing)
+	}
+
+	tokens, err = lex(source)
+	assert.Nil(t, err)
+	for _, tok := range tokens {
+		assert.Nil(t, tok.leading)
+		assert.Nil(t, tok.trailing)
+	}
+}
+
+func TestLexWithOptions_dialect(t *testing.T) {
+	type lexed struct {
+		value string
+		kind  tokenKind
+	}
+
+	tests := []struct {
+		dialect Dialect
+		input   string
+		tokens  []lexed
+	}{
+		{
+			dialect: PostgreSQLDialect,
+			input:   `"Id" returning E'a\'' 'b\' limit`,
+			tokens: []lexed{
+				{"Id", quotedIdentifierKind},
+				{"returning", keywordKind},
+				{`a\'`, escapeStringKind},
+				{`b\`, stringKind},
+				{"limit", keywordKind},
+			},
+		},
+		{
+			dialect: MySQLDialect,
+			input:   "`Id` \"it's \"\"x\"\"\" 'a\\'b' returning duplicate",
+			tokens: []lexed{
+				{"Id", quotedIdentifierKind},
+				{`it''s "x"`, stringKind},
+				{`a''b`, stringKind},
+				{"returning", identifierKind},
+				{"duplicate", keywordKind},
+			},
+		},
+		{
+			dialect: SQLiteDialect,
+			input:   "[Id] `Name` \"Age\" x'ff'",
+			tokens: []lexed{
+				{"Id", quotedIdentifierKind},
+				{"Name", quotedIdentifierKind},
+				{"Age", quotedIdentifierKind},
+				{"ff", hexStringKind},
+			},
+		},
+		{
+			dialect: ANSIDialect,
+			input:   "limit fetch",
+			tokens: []lexed{
+				{"limit", identifierKind},
+				{"fetch", keywordKind},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tokens, err := lexWithOptions(test.input, lexOptions{dialect: test.dialect})
+		if !assert.Nil(t, err, test.input) {
+			continue
+		}
+
+		actual := []lexed{}
+		for _, tok := range tokens {
+			actual = append(actual, lexed{tok.value, tok.kind})
+		}
+		assert.Equal(t, test.tokens, actual, test.input)
+	}
+
+	_, err := lexWithOptions("SELECT\n  `id`", lexOptions{dialect: PostgreSQLDialect})
+	assert.EqualError(t, err, "Unable to lex token after select, at 2:3")
+}
+
 func TestLex(t *testing.T) {
 	tests := []struct {
 		input  string
@@ -289,12 +615,14 @@ func TestLex(t *testing.T) {
 			input: "select a",
 			tokens: []token{
 				{
-					loc:   location{col: 0, line: 0},
+					loc:   location{col: 0, line: 0, offset: 0},
+					end:   location{col: 6, line: 0, offset: 6},
 					value: string(selectKeyword),
 					kind:  keywordKind,
 				},
 				{
-					loc:   location{col: 7, line: 0},
+					loc:   location{col: 7, line: 0, offset: 7},
+					end:   location{col: 8, line: 0, offset: 8},
 					value: "a",
 					kind:  identifierKind,
 				},
@@ -304,12 +632,14 @@ func TestLex(t *testing.T) {
 			input: "select true",
 			tokens: []token{
 				{
-					loc:   location{col: 0, line: 0},
+					loc:   location{col: 0, line: 0, offset: 0},
+					end:   location{col: 6, line: 0, offset: 6},
 					value: string(selectKeyword),
 					kind:  keywordKind,
 				},
 				{
-					loc:   location{col: 7, line: 0},
+					loc:   location{col: 7, line: 0, offset: 7},
+					end:   location{col: 11, line: 0, offset: 11},
 					value: "true",
 					kind:  boolKind,
 				},
@@ -319,12 +649,14 @@ func TestLex(t *testing.T) {
 			input: "select 1",
 			tokens: []token{
 				{
-					loc:   location{col: 0, line: 0},
+					loc:   location{col: 0, line: 0, offset: 0},
+					end:   location{col: 6, line: 0, offset: 6},
 					value: string(selectKeyword),
 					kind:  keywordKind,
 				},
 				{
-					loc:   location{col: 7, line: 0},
+					loc:   location{col: 7, line: 0, offset: 7},
+					end:   location{col: 8, line: 0, offset: 8},
 					value: "1",
 					kind:  numericKind,
 				},
@@ -335,27 +667,32 @@ func TestLex(t *testing.T) {
 			input: "select 'foo' || 'bar';",
 			tokens: []token{
 				{
-					loc:   location{col: 0, line: 0},
+					loc:   location{col: 0, line: 0, offset: 0},
+					end:   location{col: 6, line: 0, offset: 6},
 					value: string(selectKeyword),
 					kind:  keywordKind,
 				},
 				{
-					loc:   location{col: 7, line: 0},
+					loc:   location{col: 7, line: 0, offset: 7},
+					end:   location{col: 12, line: 0, offset: 12},
 					value: "foo",
 					kind:  stringKind,
 				},
 				{
-					loc:   location{col: 13, line: 0},
+					loc:   location{col: 13, line: 0, offset: 13},
+					end:   location{col: 15, line: 0, offset: 15},
 					value: string(concatSymbol),
 					kind:  symbolKind,
 				},
 				{
-					loc:   location{col: 16, line: 0},
+					loc:   location{col: 16, line: 0, offset: 16},
+					end:   location{col: 21, line: 0, offset: 21},
 					value: "bar",
 					kind:  stringKind,
 				},
 				{
-					loc:   location{col: 21, line: 0},
+					loc:   location{col: 21, line: 0, offset: 21},
+					end:   location{col: 22, line: 0, offset: 22},
 					value: string(semicolonSymbol),
 					kind:  symbolKind,
 				},
@@ -366,52 +703,62 @@ func TestLex(t *testing.T) {
 			input: "CREATE TABLE u (id INT, name TEXT)",
 			tokens: []token{
 				{
-					loc:   location{col: 0, line: 0},
+					loc:   location{col: 0, line: 0, offset: 0},
+					end:   location{col: 6, line: 0, offset: 6},
 					value: string(createKeyword),
 					kind:  keywordKind,
 				},
 				{
-					loc:   location{col: 7, line: 0},
+					loc:   location{col: 7, line: 0, offset: 7},
+					end:   location{col: 12, line: 0, offset: 12},
 					value: string(tableKeyword),
 					kind:  keywordKind,
 				},
 				{
-					loc:   location{col: 13, line: 0},
+					loc:   location{col: 13, line: 0, offset: 13},
+					end:   location{col: 14, line: 0, offset: 14},
 					value: "u",
 					kind:  identifierKind,
 				},
 				{
-					loc:   location{col: 15, line: 0},
+					loc:   location{col: 15, line: 0, offset: 15},
+					end:   location{col: 16, line: 0, offset: 16},
 					value: "(",
 					kind:  symbolKind,
 				},
 				{
-					loc:   location{col: 16, line: 0},
+					loc:   location{col: 16, line: 0, offset: 16},
+					end:   location{col: 18, line: 0, offset: 18},
 					value: "id",
 					kind:  identifierKind,
 				},
 				{
-					loc:   location{col: 19, line: 0},
+					loc:   location{col: 19, line: 0, offset: 19},
+					end:   location{col: 22, line: 0, offset: 22},
 					value: "int",
 					kind:  keywordKind,
 				},
 				{
-					loc:   location{col: 22, line: 0},
+					loc:   location{col: 22, line: 0, offset: 22},
+					end:   location{col: 23, line: 0, offset: 23},
 					value: ",",
 					kind:  symbolKind,
 				},
 				{
-					loc:   location{col: 24, line: 0},
+					loc:   location{col: 24, line: 0, offset: 24},
+					end:   location{col: 28, line: 0, offset: 28},
 					value: "name",
 					kind:  identifierKind,
 				},
 				{
-					loc:   location{col: 29, line: 0},
+					loc:   location{col: 29, line: 0, offset: 29},
+					end:   location{col: 33, line: 0, offset: 33},
 					value: "text",
 					kind:  keywordKind,
 				},
 				{
-					loc:   location{col: 33, line: 0},
+					loc:   location{col: 33, line: 0, offset: 33},
+					end:   location{col: 34, line: 0, offset: 34},
 					value: ")",
 					kind:  symbolKind,
 				},
@@ -421,78 +768,196 @@ func TestLex(t *testing.T) {
 			input: "insert into users values (105, 233)",
 			tokens: []token{
 				{
-					loc:   location{col: 0, line: 0},
+					loc:   location{col: 0, line: 0, offset: 0},
+					end:   location{col: 6, line: 0, offset: 6},
 					value: string(insertKeyword),
 					kind:  keywordKind,
 				},
 				{
-					loc:   location{col: 7, line: 0},
+					loc:   location{col: 7, line: 0, offset: 7},
+					end:   location{col: 11, line: 0, offset: 11},
 					value: string(intoKeyword),
 					kind:  keywordKind,
 				},
 				{
-					loc:   location{col: 12, line: 0},
+					loc:   location{col: 12, line: 0, offset: 12},
+					end:   location{col: 17, line: 0, offset: 17},
 					value: "users",
 					kind:  identifierKind,
 				},
 				{
-					loc:   location{col: 18, line: 0},
+					loc:   location{col: 18, line: 0, offset: 18},
+					end:   location{col: 24, line: 0, offset: 24},
 					value: string(valuesKeyword),
 					kind:  keywordKind,
 				},
 				{
-					loc:   location{col: 25, line: 0},
+					loc:   location{col: 25, line: 0, offset: 25},
+					end:   location{col: 26, line: 0, offset: 26},
 					value: "(",
 					kind:  symbolKind,
 				},
 				{
-					loc:   location{col: 26, line: 0},
+					loc:   location{col: 26, line: 0, offset: 26},
+					end:   location{col: 29, line: 0, offset: 29},
 					value: "105",
 					kind:  numericKind,
 				},
 				{
-					loc:   location{col: 30, line: 0},
+					loc:   location{col: 29, line: 0, offset: 29},
+					end:   location{col: 30, line: 0, offset: 30},
 					value: ",",
 					kind:  symbolKind,
 				},
 				{
-					loc:   location{col: 32, line: 0},
+					loc:   location{col: 31, line: 0, offset: 31},
+					end:   location{col: 34, line: 0, offset: 34},
 					value: "233",
 					kind:  numericKind,
 				},
 				{
-					loc:   location{col: 36, line: 0},
+					loc:   location{col: 34, line: 0, offset: 34},
+					end:   location{col: 35, line: 0, offset: 35},
 					value: ")",
 					kind:  symbolKind,
 				},
 			},
 			err: nil,
 		},
+		{
+			input: "select /* all */ a -- comment",
+			tokens: []token{
+				{
+					loc:   location{col: 0, line: 0, offset: 0},
+					end:   location{col: 6, line: 0, offset: 6},
+					value: string(selectKeyword),
+					kind:  keywordKind,
+				},
+				{
+					loc:   location{col: 17, line: 0, offset: 17},
+					end:   location{col: 18, line: 0, offset: 18},
+					value: "a",
+					kind:  identifierKind,
+				},
+			},
+		},
+		{
+			input: "select\n-- comment\na",
+			tokens: []token{
+				{
+					loc:   location{col: 0, line: 0, offset: 0},
+					end:   location{col: 6, line: 0, offset: 6},
+					value: string(selectKeyword),
+					kind:  keywordKind,
+				},
+				{
+					loc:   location{col: 0, line: 2, offset: 18},
+					end:   location{col: 1, line: 2, offset: 19},
+					value: "a",
+					kind:  identifierKind,
+				},
+			},
+		},
+		{
+			input: "select 名前, 'ü' from\tstraße",
+			tokens: []token{
+				{
+					loc:   location{col: 0, line: 0, offset: 0},
+					end:   location{col: 6, line: 0, offset: 6},
+					value: string(selectKeyword),
+					kind:  keywordKind,
+				},
+				{
+					loc:   location{col: 7, line: 0, offset: 7},
+					end:   location{col: 9, line: 0, offset: 13},
+					value: "名前",
+					kind:  identifierKind,
+				},
+				{
+					loc:   location{col: 9, line: 0, offset: 13},
+					end:   location{col: 10, line: 0, offset: 14},
+					value: ",",
+					kind:  symbolKind,
+				},
+				{
+					loc:   location{col: 11, line: 0, offset: 15},
+					end:   location{col: 14, line: 0, offset: 19},
+					value: "ü",
+					kind:  stringKind,
+				},
+				{
+					loc:   location{col: 15, line: 0, offset: 20},
+					end:   location{col: 19, line: 0, offset: 24},
+					value: string(fromKeyword),
+					kind:  keywordKind,
+				},
+				{
+					loc:   location{col: 20, line: 0, offset: 25},
+					end:   location{col: 26, line: 0, offset: 32},
+					value: "straße",
+					kind:  identifierKind,
+				},
+			},
+		},
+		{
+			input: "select 'a\nb', c",
+			tokens: []token{
+				{
+					loc:   location{col: 0, line: 0, offset: 0},
+					end:   location{col: 6, line: 0, offset: 6},
+					value: string(selectKeyword),
+					kind:  keywordKind,
+				},
+				{
+					loc:   location{col: 7, line: 0, offset: 7},
+					end:   location{col: 2, line: 1, offset: 12},
+					value: "a\nb",
+					kind:  stringKind,
+				},
+				{
+					loc:   location{col: 2, line: 1, offset: 12},
+					end:   location{col: 3, line: 1, offset: 13},
+					value: ",",
+					kind:  symbolKind,
+				},
+				{
+					loc:   location{col: 4, line: 1, offset: 14},
+					end:   location{col: 5, line: 1, offset: 15},
+					value: "c",
+					kind:  identifierKind,
+				},
+			},
+		},
 		{
 			input: "SELECT id FROM users;",
 			tokens: []token{
 				{
-					loc:   location{col: 0, line: 0},
+					loc:   location{col: 0, line: 0, offset: 0},
+					end:   location{col: 6, line: 0, offset: 6},
 					value: string(selectKeyword),
 					kind:  keywordKind,
 				},
 				{
-					loc:   location{col: 7, line: 0},
+					loc:   location{col: 7, line: 0, offset: 7},
+					end:   location{col: 9, line: 0, offset: 9},
 					value: "id",
 					kind:  identifierKind,
 				},
 				{
-					loc:   location{col: 10, line: 0},
+					loc:   location{col: 10, line: 0, offset: 10},
+					end:   location{col: 14, line: 0, offset: 14},
 					value: string(fromKeyword),
 					kind:  keywordKind,
 				},
 				{
-					loc:   location{col: 15, line: 0},
+					loc:   location{col: 15, line: 0, offset: 15},
+					end:   location{col: 20, line: 0, offset: 20},
 					value: "users",
 					kind:  identifierKind,
 				},
 				{
-					loc:   location{col: 20, line: 0},
+					loc:   location{col: 20, line: 0, offset: 20},
+					end:   location{col: 21, line: 0, offset: 21},
 					value: ";",
 					kind:  symbolKind,
 				},
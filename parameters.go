@@ -0,0 +1,182 @@
+package gosqlshell
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Parameter describes a bind parameter placeholder found in a query
+type Parameter struct {
+	// Placeholder is the parameter as written, e.g. $1, ?, :id or @id
+	Placeholder string
+	// Position is the 1-based position of positional parameters
+	Position uint
+	// Name is set for named parameters, without the leading : or @
+	Name string
+}
+
+// walkParameters calls fn for each placeholder under root, in the order
+// they appear in the source
+func walkParameters(root Node, fn func(*Cursor, Parameter)) {
+	questionMarks := uint(0)
+
+	Apply(root, func(c *Cursor) bool {
+		pe, ok := c.Node().(*ParameterExpression)
+		if !ok {
+			return true
+		}
+
+		param := Parameter{Placeholder: pe.Placeholder}
+		switch pe.Placeholder[0] {
+		case '?':
+			questionMarks++
+			param.Position = questionMarks
+		case '$':
+			position, _ := strconv.ParseUint(pe.Placeholder[1:], 10, 64)
+			param.Position = uint(position)
+		default:
+			param.Name = pe.Placeholder[1:]
+		}
+
+		fn(c, param)
+		return true
+	}, nil)
+}
+
+// Parameters lists every bind parameter placeholder in the order they
+// appear in the source. Repeated placeholders such as $1 are listed once
+// per occurrence.
+func (a *Ast) Parameters() []Parameter {
+	params := []Parameter{}
+	walkParameters(a, func(_ *Cursor, param Parameter) {
+		params = append(params, param)
+	})
+
+	return params
+}
+
+// Bind substitutes positional parameters with the given values, $1 and the
+// first ? being bound to args[0]. Supported values are strings, booleans,
+// integers, floats and nil. The Ast is left untouched if binding fails.
+func (a *Ast) Bind(args ...interface{}) error {
+	return a.bind(func(param Parameter) (interface{}, error) {
+		if param.Name != "" {
+			return nil, fmt.Errorf("Named parameter %s can't be bound by position", param.Placeholder)
+		}
+
+		if param.Position == 0 || param.Position > uint(len(args)) {
+			return nil, fmt.Errorf("Missing value for parameter %s, got %d arguments", param.Placeholder, len(args))
+		}
+
+		return args[param.Position-1], nil
+	})
+}
+
+// BindNamed substitutes named parameters like :id or @id with the values of
+// the matching keys. The Ast is left untouched if binding fails.
+func (a *Ast) BindNamed(args map[string]interface{}) error {
+	return a.bind(func(param Parameter) (interface{}, error) {
+		if param.Name == "" {
+			return nil, fmt.Errorf("Positional parameter %s can't be bound by name", param.Placeholder)
+		}
+
+		value, ok := args[param.Name]
+		if !ok {
+			return nil, fmt.Errorf("Missing value for parameter %s", param.Placeholder)
+		}
+
+		return value, nil
+	})
+}
+
+func (a *Ast) bind(value func(Parameter) (interface{}, error)) error {
+	// resolve everything first so that a failure doesn't leave the tree
+	// partially bound
+	var literals []*Literal
+	var err error
+	walkParameters(a, func(c *Cursor, param Parameter) {
+		if err != nil {
+			return
+		}
+
+		var v interface{}
+		v, err = value(param)
+		if err != nil {
+			return
+		}
+
+		var literal *Literal
+		literal, err = literalFromValue(v)
+		if err != nil {
+			err = fmt.Errorf("Unable to bind parameter %s: %s", param.Placeholder, err)
+			return
+		}
+
+		// the literal takes the place of the parameter in the source
+		literal.span = span{pos: c.Node().Pos(), end: c.Node().End()}
+		literals = append(literals, literal)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	walkParameters(a, func(c *Cursor, _ Parameter) {
+		c.Replace(literals[0])
+		literals = literals[1:]
+	})
+
+	return nil
+}
+
+func literalFromValue(v interface{}) (*Literal, error) {
+	switch v := v.(type) {
+	case nil:
+		return &Literal{Kind: NullLiteral, Value: "NULL"}, nil
+	case string:
+		// string literals hold the SQL escaped form of the value
+		return &Literal{Kind: StringLiteral, Value: strings.ReplaceAll(v, "'", "''")}, nil
+	case bool:
+		return &Literal{Kind: BoolLiteral, Value: strconv.FormatBool(v)}, nil
+	case int:
+		return numericLiteral(strconv.FormatInt(int64(v), 10)), nil
+	case int8:
+		return numericLiteral(strconv.FormatInt(int64(v), 10)), nil
+	case int16:
+		return numericLiteral(strconv.FormatInt(int64(v), 10)), nil
+	case int32:
+		return numericLiteral(strconv.FormatInt(int64(v), 10)), nil
+	case int64:
+		return numericLiteral(strconv.FormatInt(v, 10)), nil
+	case uint:
+		return numericLiteral(strconv.FormatUint(uint64(v), 10)), nil
+	case uint8:
+		return numericLiteral(strconv.FormatUint(uint64(v), 10)), nil
+	case uint16:
+		return numericLiteral(strconv.FormatUint(uint64(v), 10)), nil
+	case uint32:
+		return numericLiteral(strconv.FormatUint(uint64(v), 10)), nil
+	case uint64:
+		return numericLiteral(strconv.FormatUint(v, 10)), nil
+	case float32:
+		return floatLiteral(float64(v), 32)
+	case float64:
+		return floatLiteral(v, 64)
+	}
+
+	return nil, fmt.Errorf("unsupported type %T", v)
+}
+
+func numericLiteral(value string) *Literal {
+	return &Literal{Kind: NumericLiteral, Value: value}
+}
+
+func floatLiteral(f float64, bitSize int) (*Literal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("%v has no SQL literal", f)
+	}
+
+	return numericLiteral(strconv.FormatFloat(f, 'g', -1, bitSize)), nil
+}
@@ -0,0 +1,162 @@
+package gosqlshell
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAst_Parameters(t *testing.T) {
+	tests := []struct {
+		source  string
+		dialect Dialect
+		params  []Parameter
+	}{
+		{
+			source: "SELECT id FROM users WHERE id = $1 AND name = $2 OR id = $1",
+			params: []Parameter{
+				{Placeholder: "$1", Position: 1},
+				{Placeholder: "$2", Position: 2},
+				{Placeholder: "$1", Position: 1},
+			},
+		},
+		{
+			source: "INSERT INTO users (id, name) VALUES (?, ?), (?, 'x')",
+			params: []Parameter{
+				{Placeholder: "?", Position: 1},
+				{Placeholder: "?", Position: 2},
+				{Placeholder: "?", Position: 3},
+			},
+		},
+		{
+			source: "UPDATE users SET name = :name WHERE id = @userId; DELETE FROM users WHERE id = :id",
+			params: []Parameter{
+				{Placeholder: ":name", Name: "name"},
+				{Placeholder: "@userId", Name: "userId"},
+				{Placeholder: ":id", Name: "id"},
+			},
+		},
+		{
+			source: "SELECT id FROM users",
+			params: []Parameter{},
+		},
+		{
+			source:  "SELECT id FROM users WHERE id = ? LIMIT ?, ?",
+			dialect: MySQLDialect,
+			params: []Parameter{
+				{Placeholder: "?", Position: 1},
+				{Placeholder: "?", Position: 2},
+				{Placeholder: "?", Position: 3},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		parser := Parser{HelpMessagesDisabled: true, Dialect: test.dialect}
+		ast, err := parser.Parse(test.source)
+		if assert.Nil(t, err, test.source) {
+			assert.Equal(t, test.params, ast.Parameters(), test.source)
+		}
+	}
+}
+
+func TestAst_Bind(t *testing.T) {
+	tests := []struct {
+		source  string
+		dialect Dialect
+		args    []interface{}
+		named   map[string]interface{}
+		result  string
+		err     bool
+	}{
+		{
+			source: "UPDATE users SET name = $2, score = $3 WHERE id = $1 AND active = $4",
+			args:   []interface{}{-7, "O'Brien", 1.5, true},
+			result: `UPDATE users SET name = 'O''Brien', score = 1.5 WHERE ((id = -7) and (active = true));`,
+		},
+		{
+			source: "INSERT INTO users VALUES (?, ?, ?)",
+			args:   []interface{}{uint8(1), nil, int64(2)},
+			result: `INSERT INTO users VALUES (1, NULL, 2);`,
+		},
+		{
+			source: "DELETE FROM users WHERE id = :id OR name = @id",
+			named:  map[string]interface{}{"id": 3},
+			result: `DELETE FROM users WHERE ((id = 3) or (name = 3));`,
+		},
+		{
+			source:  "SELECT id FROM users LIMIT ?, ?",
+			dialect: MySQLDialect,
+			args:    []interface{}{20, 10},
+			result: `SELECT
+	id
+FROM
+	users
+LIMIT 10
+OFFSET 20;`,
+		},
+		// failures
+		{
+			source: "DELETE FROM users WHERE id = $2",
+			args:   []interface{}{1},
+			err:    true,
+		},
+		{
+			source: "DELETE FROM users WHERE id = :id",
+			args:   []interface{}{1},
+			err:    true,
+		},
+		{
+			source: "DELETE FROM users WHERE id = $1",
+			named:  map[string]interface{}{"id": 1},
+			err:    true,
+		},
+		{
+			source: "DELETE FROM users WHERE id = :id",
+			named:  map[string]interface{}{"name": 1},
+			err:    true,
+		},
+		{
+			source: "DELETE FROM users WHERE id = ? AND score = ?",
+			args:   []interface{}{1, math.NaN()},
+			err:    true,
+		},
+		{
+			source: "DELETE FROM users WHERE id = ? AND score = ?",
+			args:   []interface{}{1, []int{1}},
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		parser := Parser{HelpMessagesDisabled: true, Dialect: test.dialect}
+		ast, err := parser.Parse(test.source)
+		if !assert.Nil(t, err, test.source) {
+			continue
+		}
+
+		before := ast.Statements[0].GenerateCode()
+		if test.named != nil {
+			err = ast.BindNamed(test.named)
+		} else {
+			err = ast.Bind(test.args...)
+		}
+
+		if test.err {
+			assert.NotNil(t, err, test.source)
+			// nothing is bound when any parameter fails
+			assert.Equal(t, before, ast.Statements[0].GenerateCode(), test.source)
+			continue
+		}
+
+		assert.Nil(t, err, test.source)
+		assert.Equal(t, test.result, ast.Statements[0].GenerateCode(), test.source)
+
+		// the generated code has to parse back to the same statement
+		reparsed, err := parser.Parse(test.result)
+		if assert.Nil(t, err, test.result) {
+			assert.Equal(t, test.result, reparsed.Statements[0].GenerateCode())
+		}
+	}
+}
@@ -3,6 +3,8 @@ package gosqlshell
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
 func tokenFromKeyword(k keyword) token {
@@ -22,6 +24,10 @@ func tokenFromSymbol(s symbol) token {
 // Parser represents the parser itself ;)
 type Parser struct {
 	HelpMessagesDisabled bool
+	Dialect              Dialect
+	// KeepComments preserves SQL comments as trivia on the parsed tokens,
+	// and on the statements they surround
+	KeepComments bool
 }
 
 func (p Parser) helpMessage(tokens []*token, cursor uint, msg string) {
@@ -29,6 +35,12 @@ func (p Parser) helpMessage(tokens []*token, cursor uint, msg string) {
 		return
 	}
 
+	fmt.Println(nearMessage(tokens, cursor, msg))
+}
+
+// nearMessage prefixes msg with the 1-based line and column of the token
+// following cursor
+func nearMessage(tokens []*token, cursor uint, msg string) string {
 	var c *token
 	if cursor+1 < uint(len(tokens)) {
 		c = tokens[cursor+1]
@@ -36,7 +48,8 @@ func (p Parser) helpMessage(tokens []*token, cursor uint, msg string) {
 		c = tokens[cursor]
 	}
 
-	fmt.Printf("[%d,%d]: %s, near: %s\n", c.loc.line, c.loc.col, msg, c.value)
+	pos := c.loc.pos()
+	return fmt.Sprintf("[%d,%d]: %s, near: %s", pos.Line, pos.Col, msg, c.value)
 }
 
 func (p Parser) parseTokenKind(tokens []*token, initialCursor uint, kind tokenKind) (*token, uint, bool) {
@@ -68,33 +81,298 @@ func (p Parser) parseToken(tokens []*token, initialCursor uint, t token) (*token
 	return nil, initialCursor, false
 }
 
-func (p Parser) parseLiteralExpression(tokens []*token, initialCursor uint) (*expression, uint, bool) {
+// pos is the 1-based position of the location
+func (l location) pos() Pos {
+	return Pos{Offset: l.offset, Line: l.line + 1, Col: l.col + 1}
+}
+
+// spanOf is the span of tokens[start:end]
+func spanOf(tokens []*token, start, end uint) span {
+	return span{
+		pos: tokens[start].loc.pos(),
+		end: tokens[end-1].end.pos(),
+	}
+}
+
+// literalKinds maps the tokens of constants to the kind of their literal
+var literalKinds = map[tokenKind]LiteralKind{
+	numericKind:        NumericLiteral,
+	stringKind:         StringLiteral,
+	escapeStringKind:   EscapeStringLiteral,
+	dollarStringKind:   DollarStringLiteral,
+	hexStringKind:      HexStringLiteral,
+	bitStringKind:      BitStringLiteral,
+	nationalStringKind: NationalStringLiteral,
+	boolKind:           BoolLiteral,
+}
+
+// parseIdentifier accepts identifiers as well as keywords that are not
+// reserved, so that columns named e.g. first or last can still be used
+func (p Parser) parseIdentifier(tokens []*token, initialCursor uint) (*Identifier, uint, bool) {
+	id, cursor, ok := p.parseTokenKind(tokens, initialCursor, identifierKind)
+	if !ok {
+		id, cursor, ok = p.parseTokenKind(tokens, initialCursor, quotedIdentifierKind)
+	}
+
+	if !ok {
+		id, cursor, ok = p.parseTokenKind(tokens, initialCursor, keywordKind)
+		if !ok || isReservedKeyword(keyword(id.value)) {
+			return nil, initialCursor, false
+		}
+	}
+
+	return &Identifier{
+		span:   spanOf(tokens, initialCursor, cursor),
+		Name:   id.value,
+		Quoted: id.kind == quotedIdentifierKind,
+	}, cursor, true
+}
+
+func (p Parser) parseQualifiedName(tokens []*token, initialCursor uint, allowAsterisk bool) (*QualifiedName, uint, bool) {
+	cursor := initialCursor
+
+	qn := QualifiedName{}
+	for {
+		part, newCursor, ok := p.parseIdentifier(tokens, cursor)
+		if !ok {
+			return nil, initialCursor, false
+		}
+		cursor = newCursor
+		qn.Parts = append(qn.Parts, part)
+
+		_, newCursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(dotSymbol))
+		if !ok {
+			break
+		}
+		cursor = newCursor
+
+		// alias.* ends the name
+		if allowAsterisk {
+			_, newCursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(asteriskSymbol))
+			if ok {
+				qn.Asterisk = true
+				cursor = newCursor
+				break
+			}
+		}
+	}
+
+	qn.span = spanOf(tokens, initialCursor, cursor)
+	return &qn, cursor, true
+}
+
+// parseValuesExpression parses MySQL's VALUES(column)
+func (p Parser) parseValuesExpression(tokens []*token, initialCursor uint) (*ValuesExpression, uint, bool) {
+	cursor := initialCursor
+	for _, t := range []token{tokenFromKeyword(valuesKeyword), tokenFromSymbol(leftParenSymbol)} {
+		var ok bool
+		_, cursor, ok = p.parseToken(tokens, cursor, t)
+		if !ok {
+			return nil, initialCursor, false
+		}
+	}
+
+	column, cursor, ok := p.parseIdentifier(tokens, cursor)
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected column name")
+		return nil, initialCursor, false
+	}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(rightParenSymbol))
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected closing paren")
+		return nil, initialCursor, false
+	}
+
+	return &ValuesExpression{
+		span:   spanOf(tokens, initialCursor, cursor),
+		Column: column,
+	}, cursor, true
+}
+
+func (p Parser) parseLiteralExpression(tokens []*token, initialCursor uint) (Expression, uint, bool) {
 	cursor := initialCursor
 
-	kinds := []tokenKind{identifierKind, numericKind, stringKind, boolKind}
-	for _, kind := range kinds {
-		t, newCursor, ok := p.parseTokenKind(tokens, cursor, kind)
+	// checked before names as date, time and interval aren't reserved
+	typed, newCursor, ok := p.parseTypedLiteral(tokens, cursor)
+	if ok {
+		return typed, newCursor, true
+	}
+
+	if p.Dialect == MySQLDialect {
+		values, newCursor, ok := p.parseValuesExpression(tokens, cursor)
 		if ok {
-			return &expression{
-				literal: t,
-				kind:    literalKind,
-			}, newCursor, true
+			return values, newCursor, true
+		}
+	}
+
+	// dotted names like users.id are column references rather than
+	// plain identifier literals
+	qn, newCursor, ok := p.parseQualifiedName(tokens, cursor, true)
+	if ok {
+		if len(qn.Parts) > 1 || qn.Asterisk {
+			return qn, newCursor, true
+		}
+
+		return qn.Parts[0], newCursor, true
+	}
+
+	param, newCursor, ok := p.parseTokenKind(tokens, cursor, parameterKind)
+	if ok {
+		return &ParameterExpression{
+			span:        spanOf(tokens, cursor, newCursor),
+			Placeholder: param.value,
+		}, newCursor, true
+	}
+
+	n, newCursor, ok := p.parseNumeric(tokens, cursor)
+	if ok {
+		return &Literal{
+			span:  spanOf(tokens, cursor, newCursor),
+			Kind:  NumericLiteral,
+			Value: n.value,
+		}, newCursor, true
+	}
+
+	_, newCursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(nullKeyword))
+	if ok {
+		return &Literal{
+			span:  spanOf(tokens, cursor, newCursor),
+			Kind:  NullLiteral,
+			Value: "NULL",
+		}, newCursor, true
+	}
+
+	if cursor < uint(len(tokens)) {
+		t := tokens[cursor]
+		if kind, ok := literalKinds[t.kind]; ok {
+			return &Literal{
+				span:  spanOf(tokens, cursor, cursor+1),
+				Kind:  kind,
+				Value: t.value,
+				Tag:   t.tag,
+			}, cursor + 1, true
 		}
 	}
 
 	return nil, initialCursor, false
 }
 
-func (p Parser) parseExpression(tokens []*token, initialCursor uint, delimiters []token, minBp uint) (*expression, uint, bool) {
+// parseNumeric parses numbers, folding a leading minus into the literal,
+// e.g. -5
+func (p Parser) parseNumeric(tokens []*token, initialCursor uint) (*token, uint, bool) {
+	cursor := initialCursor
+
+	minus, cursor, hasMinus := p.parseToken(tokens, cursor, tokenFromSymbol(minusSymbol))
+	n, cursor, ok := p.parseTokenKind(tokens, cursor, numericKind)
+	if !ok {
+		return nil, initialCursor, false
+	}
+
+	if !hasMinus {
+		return n, cursor, true
+	}
+
+	return &token{
+		value: minus.value + n.value,
+		kind:  numericKind,
+		loc:   minus.loc,
+		end:   n.end,
+	}, cursor, true
+}
+
+// parseTypedLiteral parses DATE, TIME and TIMESTAMP literals as well as
+// intervals, either '3 days', '3' DAY or, for MySQL, 3 DAY
+func (p Parser) parseTypedLiteral(tokens []*token, initialCursor uint) (*TypedLiteral, uint, bool) {
+	cursor := initialCursor
+
+	layouts := map[keyword][]string{
+		dateKeyword:      dateLayouts,
+		timeKeyword:      timeLayouts,
+		timestampKeyword: timestampLayouts,
+	}
+
+	typ, cursor, ok := p.parseTokenKind(tokens, cursor, keywordKind)
+	if !ok {
+		return nil, initialCursor, false
+	}
+
+	k := keyword(typ.value)
+	if _, ok := layouts[k]; !ok && k != intervalKeyword {
+		return nil, initialCursor, false
+	}
+
+	value, newCursor, ok := p.parseTokenKind(tokens, cursor, stringKind)
+	if !ok && k == intervalKeyword && p.Dialect == MySQLDialect {
+		value, newCursor, ok = p.parseNumeric(tokens, cursor)
+	}
+
+	if !ok {
+		return nil, initialCursor, false
+	}
+
+	tl := TypedLiteral{
+		Type: strings.ToUpper(typ.value),
+		Value: &Literal{
+			span:  spanOf(tokens, cursor, newCursor),
+			Kind:  literalKinds[value.kind],
+			Value: value.value,
+		},
+	}
+	if k != intervalKeyword {
+		t, err := parseTime(value.value, layouts[k])
+		if err != nil {
+			p.helpMessage(tokens, cursor-1, fmt.Sprintf("Invalid %s literal, %s", tl.Type, err))
+			return nil, initialCursor, false
+		}
+
+		tl.span = spanOf(tokens, initialCursor, newCursor)
+		tl.Time = t
+		return &tl, newCursor, true
+	}
+
+	text := value.value
+	_, err := strconv.ParseFloat(text, 64)
+	isNumber := err == nil
+
+	// a unit only follows single numbers and is always singular, anything
+	// else is read as an alias
+	unit, unitCursor, ok := p.parseTokenKind(tokens, newCursor, identifierKind)
+	if ok && isNumber {
+		if _, isUnit := intervalUnits[unit.value]; isUnit {
+			tl.Unit = strings.ToUpper(unit.value)
+			text = fmt.Sprintf("%s %s", text, unit.value)
+			newCursor = unitCursor
+		}
+	}
+
+	if value.kind == numericKind && tl.Unit == "" {
+		p.helpMessage(tokens, cursor, "Expected interval unit")
+		return nil, initialCursor, false
+	}
+
+	iv, err := parseInterval(text)
+	if err != nil {
+		p.helpMessage(tokens, cursor-1, fmt.Sprintf("Invalid INTERVAL literal, %s", err))
+		return nil, initialCursor, false
+	}
+
+	tl.span = spanOf(tokens, initialCursor, newCursor)
+	tl.Interval = iv
+	return &tl, newCursor, true
+}
+
+func (p Parser) parseExpression(tokens []*token, initialCursor uint, delimiters []token, minBp uint) (Expression, uint, bool) {
 	cursor := initialCursor
 
-	var exp *expression
+	var exp Expression
 	_, newCursor, ok := p.parseToken(tokens, cursor, tokenFromSymbol(leftParenSymbol))
 	if ok {
 		cursor = newCursor
 		rightParenToken := tokenFromSymbol(rightParenSymbol)
 
-		exp, cursor, ok = p.parseExpression(tokens, cursor, append(delimiters, rightParenToken), minBp)
+		// parentheses reset the precedence, e.g. a = (b or c)
+		exp, cursor, ok = p.parseExpression(tokens, cursor, append(delimiters, rightParenToken), 0)
 		if !ok {
 			p.helpMessage(tokens, cursor, "Exprected expression after opening paren")
 			return nil, initialCursor, false
@@ -123,6 +401,34 @@ outer:
 			}
 		}
 
+		// IN compares like = does, its list being in parentheses
+		inCursor := cursor
+		_, inCursor, not := p.parseToken(tokens, inCursor, tokenFromKeyword(notKeyword))
+		in, inCursor, ok := p.parseToken(tokens, inCursor, tokenFromKeyword(inKeyword))
+		if ok {
+			if in.bindingPower() < minBp {
+				cursor = lastCursor
+				break
+			}
+
+			list, newCursor, ok := p.parseInList(tokens, inCursor)
+			if !ok {
+				return nil, initialCursor, false
+			}
+
+			exp = &InExpression{
+				span:       spanOf(tokens, initialCursor, newCursor),
+				Expression: exp,
+				Not:        not,
+				List:       list,
+			}
+			cursor = newCursor
+			lastCursor = cursor
+			continue
+		} else if not {
+			break
+		}
+
 		binOps := []token{
 			tokenFromKeyword(andKeyword),
 			tokenFromKeyword(orKeyword),
@@ -130,6 +436,7 @@ outer:
 			tokenFromSymbol(neqSymbol),
 			tokenFromSymbol(concatSymbol),
 			tokenFromSymbol(plusSymbol),
+			tokenFromSymbol(minusSymbol),
 		}
 
 		var op *token
@@ -142,9 +449,9 @@ outer:
 			}
 		}
 
+		// anything else ends the expression, e.g. an implicit alias
 		if op == nil {
-			p.helpMessage(tokens, cursor, "Expected binary operator")
-			return nil, initialCursor, false
+			break
 		}
 
 		bp := op.bindingPower()
@@ -153,18 +460,18 @@ outer:
 			break
 		}
 
-		b, newCursor, ok := p.parseExpression(tokens, cursor, delimiters, bp)
+		// operators of the same precedence group to the left, e.g. a - b - c
+		// is (a - b) - c
+		b, newCursor, ok := p.parseExpression(tokens, cursor, delimiters, bp+1)
 		if !ok {
 			p.helpMessage(tokens, cursor, "Expected right operand")
 			return nil, initialCursor, false
 		}
-		exp = &expression{
-			binary: &binaryExpression{
-				*exp,
-				*b,
-				*op,
-			},
-			kind: binaryKind,
+		exp = &BinaryExpression{
+			span:     spanOf(tokens, initialCursor, newCursor),
+			Left:     exp,
+			Right:    b,
+			Operator: op.value,
 		}
 		cursor = newCursor
 		lastCursor = cursor
@@ -173,10 +480,92 @@ outer:
 	return exp, cursor, true
 }
 
-func (p Parser) parseSelectItem(tokens []*token, initialCursor uint, delimiters []token) (*[]*selectItem, uint, bool) {
+// parseInList parses the parenthesized list of expressions of IN
+func (p Parser) parseInList(tokens []*token, initialCursor uint) ([]Expression, uint, bool) {
+	_, cursor, ok := p.parseToken(tokens, initialCursor, tokenFromSymbol(leftParenSymbol))
+	if !ok {
+		p.helpMessage(tokens, initialCursor, "Expected opening paren after IN")
+		return nil, initialCursor, false
+	}
+
+	rightParenToken := tokenFromSymbol(rightParenSymbol)
+	list, cursor, ok := p.parseExpressions(tokens, cursor, rightParenToken)
+	if !ok {
+		return nil, initialCursor, false
+	}
+
+	for _, exp := range list {
+		if l, ok := exp.(*Literal); ok && l.Kind == DefaultLiteral {
+			p.helpMessage(tokens, cursor, "DEFAULT is only allowed in VALUES")
+			return nil, initialCursor, false
+		}
+	}
+
+	if len(list) == 0 {
+		p.helpMessage(tokens, cursor, "Expected expression")
+		return nil, initialCursor, false
+	}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, rightParenToken)
+	if !ok {
+		p.helpMessage(tokens, cursor, "Exprected closing paren")
+		return nil, initialCursor, false
+	}
+
+	return list, cursor, true
+}
+
+// parseAlias parses an optional alias, either after AS or implicitly as a
+// bare identifier. A nil alias is returned when there is none.
+func (p Parser) parseAlias(tokens []*token, initialCursor uint) (*Identifier, uint, bool) {
+	cursor := initialCursor
+
+	_, cursor, ok := p.parseToken(tokens, cursor, tokenFromKeyword(asKeyword))
+	if ok {
+		id, newCursor, ok := p.parseIdentifier(tokens, cursor)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected identifier after AS")
+			return nil, initialCursor, false
+		}
+
+		return id, newCursor, true
+	}
+
+	// reserved keywords such as FROM or WHERE are never implicit aliases
+	id, newCursor, ok := p.parseIdentifier(tokens, cursor)
+	if ok {
+		return id, newCursor, true
+	}
+
+	return nil, initialCursor, true
+}
+
+func (p Parser) parseTableReference(tokens []*token, initialCursor uint) (*TableReference, uint, bool) {
+	cursor := initialCursor
+
+	name, newCursor, ok := p.parseQualifiedName(tokens, cursor, false)
+	if !ok {
+		return nil, initialCursor, false
+	}
+	cursor = newCursor
+
+	as, newCursor, ok := p.parseAlias(tokens, cursor)
+	if !ok {
+		return nil, initialCursor, false
+	}
+	cursor = newCursor
+
+	return &TableReference{
+		span:  spanOf(tokens, initialCursor, cursor),
+		Name:  name,
+		Alias: as,
+	}, cursor, true
+}
+
+func (p Parser) parseSelectItem(tokens []*token, initialCursor uint, delimiters []token) ([]*SelectItem, uint, bool) {
 	cursor := initialCursor
 
-	var s []*selectItem
+	var s []*SelectItem
 outer:
 	for {
 		if cursor >= uint(len(tokens)) {
@@ -199,10 +588,11 @@ outer:
 			}
 		}
 
-		var si selectItem
+		itemCursor := cursor
+		var si SelectItem
 		_, cursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(asteriskSymbol))
 		if ok {
-			si = selectItem{asterisk: true}
+			si = SelectItem{Asterisk: true}
 		} else {
 			asToken := tokenFromKeyword(asKeyword)
 			delimiters := append(delimiters, tokenFromSymbol(commaSymbol), asToken)
@@ -213,25 +603,22 @@ outer:
 			}
 
 			cursor = newCursor
-			si.exp = exp
-
-			_, cursor, ok = p.parseToken(tokens, cursor, asToken)
-			if ok {
-				id, newCursor, ok := p.parseTokenKind(tokens, cursor, identifierKind)
-				if !ok {
-					p.helpMessage(tokens, cursor, "Expected identifier after AS")
-					return nil, initialCursor, false
-				}
+			si.Expression = exp
 
-				cursor = newCursor
-				si.as = id
+			as, newCursor, ok := p.parseAlias(tokens, cursor)
+			if !ok {
+				return nil, initialCursor, false
 			}
+
+			cursor = newCursor
+			si.Alias = as
 		}
 
+		si.span = spanOf(tokens, itemCursor, cursor)
 		s = append(s, &si)
 	}
 
-	return &s, cursor, true
+	return s, cursor, true
 }
 
 func (p Parser) parseSelectStatement(tokens []*token, initialCursor uint, delimiter token) (*SelectStatement, uint, bool) {
@@ -250,20 +637,20 @@ func (p Parser) parseSelectStatement(tokens []*token, initialCursor uint, delimi
 		return nil, initialCursor, false
 	}
 
-	slct.item = item
+	slct.Items = item
 	cursor = newCursor
 
 	whereToken := tokenFromKeyword(whereKeyword)
 
 	_, cursor, ok = p.parseToken(tokens, cursor, fromToken)
 	if ok {
-		from, newCursor, ok := p.parseToken(tokens, cursor, fromToken)
+		from, newCursor, ok := p.parseTableReference(tokens, cursor)
 		if !ok {
 			p.helpMessage(tokens, cursor, "Expected FROM item")
 			return nil, initialCursor, false
 		}
 
-		slct.from = from
+		slct.From = from
 		cursor = newCursor
 	}
 
@@ -275,272 +662,1404 @@ func (p Parser) parseSelectStatement(tokens []*token, initialCursor uint, delimi
 			return nil, initialCursor, false
 		}
 
-		slct.where = where
+		slct.Where = where
+		cursor = newCursor
+	}
+
+	limit, newCursor, ok := p.parseLimitClause(tokens, cursor, delimiter)
+	if ok {
+		slct.Limit = limit
 		cursor = newCursor
+	} else if newCursor != cursor {
+		return nil, initialCursor, false
 	}
 
+	slct.span = spanOf(tokens, initialCursor, cursor)
 	return &slct, cursor, true
 }
 
-func (p Parser) parseExpressions(tokens []*token, initialCursor uint, delimiter token) (*[]*expression, uint, bool) {
+// parseLimitClause parses LIMIT count [OFFSET skip], LIMIT skip, count and
+// the standard OFFSET skip ROWS FETCH FIRST count ROWS ONLY. A clause that
+// starts but doesn't parse moves the cursor to where it failed.
+func (p Parser) parseLimitClause(tokens []*token, initialCursor uint, delimiter token) (*LimitClause, uint, bool) {
 	cursor := initialCursor
+	delimiters := []token{delimiter}
 
-	var exps []*expression
-	for {
-		if cursor >= uint(len(tokens)) {
-			return nil, initialCursor, false
-		}
+	lc := LimitClause{}
 
-		current := tokens[cursor]
-		if delimiter.equals(current) {
-			break
+	_, newCursor, ok := p.parseToken(tokens, cursor, tokenFromKeyword(limitKeyword))
+	if ok {
+		cursor = newCursor
+		count, newCursor, ok := p.parseExpression(tokens, cursor, delimiters, 0)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected LIMIT count")
+			return nil, cursor, false
 		}
+		cursor = newCursor
 
-		if len(exps) > 0 {
-			var ok bool
-			_, cursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(commaSymbol))
+		_, newCursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(commaSymbol))
+		if ok && p.Dialect.hasLimitComma() {
+			cursor = newCursor
+			lc.Offset = count
+			lc.Comma = true
+
+			count, newCursor, ok = p.parseExpression(tokens, cursor, delimiters, 0)
 			if !ok {
-				p.helpMessage(tokens, cursor, "Expected comma")
-				return nil, initialCursor, false
+				p.helpMessage(tokens, cursor, "Expected LIMIT count")
+				return nil, cursor, false
 			}
+			cursor = newCursor
 		}
 
-		exp, newCursor, ok := p.parseExpression(tokens, cursor, []token{tokenFromSymbol(commaSymbol), tokenFromSymbol(rightParenSymbol)}, 0)
+		lc.Count = count
+	}
+
+	_, newCursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(offsetKeyword))
+	if ok && lc.Offset == nil {
+		cursor = newCursor
+		offset, newCursor, ok := p.parseExpression(tokens, cursor, delimiters, 0)
 		if !ok {
-			p.helpMessage(tokens, cursor, "Expected expression")
-			return nil, initialCursor, false
+			p.helpMessage(tokens, cursor, "Expected OFFSET value")
+			return nil, cursor, false
+		}
+		cursor = p.parseRows(tokens, newCursor)
+
+		lc.Offset = offset
+	}
+
+	_, newCursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(fetchKeyword))
+	if ok && lc.Count == nil {
+		cursor = newCursor
+		count, newCursor, ok := p.parseFetch(tokens, cursor)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected FETCH FIRST count ROWS ONLY")
+			return nil, cursor, false
 		}
 		cursor = newCursor
 
-		exps = append(exps, exp)
+		lc.Count = count
+	}
+
+	if lc.Count == nil && lc.Offset == nil {
+		return nil, initialCursor, false
+	}
+
+	lc.span = spanOf(tokens, initialCursor, cursor)
+	return &lc, cursor, true
+}
+
+// parseRows skips the optional ROW or ROWS noise word
+func (p Parser) parseRows(tokens []*token, cursor uint) uint {
+	for _, k := range []keyword{rowKeyword, rowsKeyword} {
+		if _, newCursor, ok := p.parseToken(tokens, cursor, tokenFromKeyword(k)); ok {
+			return newCursor
+		}
 	}
 
-	return &exps, cursor, true
+	return cursor
 }
 
-func (p Parser) parseInsertStatement(tokens []*token, initialCursor uint, _ token) (*InsertStatement, uint, bool) {
+// parseFetch parses the part of FETCH FIRST count ROWS ONLY after FETCH
+func (p Parser) parseFetch(tokens []*token, initialCursor uint) (Expression, uint, bool) {
 	cursor := initialCursor
-	ok := false
 
-	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(insertKeyword))
+	_, cursor, ok := p.parseToken(tokens, cursor, tokenFromKeyword(firstKeyword))
 	if !ok {
-		return nil, initialCursor, false
+		_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(nextKeyword))
+		if !ok {
+			return nil, initialCursor, false
+		}
 	}
 
-	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(intoKeyword))
+	count, cursor, ok := p.parseExpression(tokens, cursor, []token{tokenFromKeyword(rowsKeyword)}, 0)
 	if !ok {
-		p.helpMessage(tokens, cursor, "Expected into")
 		return nil, initialCursor, false
 	}
 
-	table, newCursor, ok := p.parseTokenKind(tokens, cursor, identifierKind)
-	if !ok {
+	newCursor := p.parseRows(tokens, cursor)
+	if newCursor == cursor {
+		return nil, initialCursor, false
+	}
+
+	_, cursor, ok = p.parseToken(tokens, newCursor, tokenFromKeyword(onlyKeyword))
+	if !ok {
+		return nil, initialCursor, false
+	}
+
+	return count, cursor, true
+}
+
+func (p Parser) parseExpressions(tokens []*token, initialCursor uint, delimiter token) ([]Expression, uint, bool) {
+	cursor := initialCursor
+
+	var exps []Expression
+	for {
+		if cursor >= uint(len(tokens)) {
+			return nil, initialCursor, false
+		}
+
+		current := tokens[cursor]
+		if delimiter.equals(current) {
+			break
+		}
+
+		if len(exps) > 0 {
+			var ok bool
+			_, cursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(commaSymbol))
+			if !ok {
+				p.helpMessage(tokens, cursor, "Expected comma")
+				return nil, initialCursor, false
+			}
+		}
+
+		// DEFAULT is only meaningful as a whole value, not inside expressions
+		_, newCursor, ok := p.parseToken(tokens, cursor, tokenFromKeyword(defaultKeyword))
+		if ok {
+			exps = append(exps, &Literal{
+				span:  spanOf(tokens, cursor, newCursor),
+				Kind:  DefaultLiteral,
+				Value: "DEFAULT",
+			})
+			cursor = newCursor
+			continue
+		}
+
+		exp, newCursor, ok := p.parseExpression(tokens, cursor, []token{tokenFromSymbol(commaSymbol), delimiter}, 0)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected expression")
+			return nil, initialCursor, false
+		}
+		cursor = newCursor
+
+		exps = append(exps, exp)
+	}
+
+	return exps, cursor, true
+}
+
+func (p Parser) parseValues(tokens []*token, initialCursor uint, delimiter token) ([][]Expression, uint, bool) {
+	cursor := initialCursor
+
+	rightParenToken := tokenFromSymbol(rightParenSymbol)
+
+	var rows [][]Expression
+	for {
+		if cursor >= uint(len(tokens)) {
+			return nil, initialCursor, false
+		}
+
+		current := tokens[cursor]
+		if delimiter.equals(current) {
+			break
+		}
+
+		var ok bool
+		if len(rows) > 0 {
+			_, cursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(commaSymbol))
+			if !ok {
+				break
+			}
+		}
+
+		_, cursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(leftParenSymbol))
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected left paren")
+			return nil, initialCursor, false
+		}
+
+		values, newCursor, ok := p.parseExpressions(tokens, cursor, rightParenToken)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected expressions")
+			return nil, initialCursor, false
+		}
+		cursor = newCursor
+
+		_, cursor, ok = p.parseToken(tokens, cursor, rightParenToken)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected right paren")
+			return nil, initialCursor, false
+		}
+
+		rows = append(rows, values)
+	}
+
+	if len(rows) == 0 {
+		p.helpMessage(tokens, cursor, "Expected values")
+		return nil, initialCursor, false
+	}
+
+	return rows, cursor, true
+}
+
+func (p Parser) parseInsertStatement(tokens []*token, initialCursor uint, delimiter token) (*InsertStatement, uint, bool) {
+	cursor := initialCursor
+	ok := false
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(insertKeyword))
+	if !ok {
+		return nil, initialCursor, false
+	}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(intoKeyword))
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected into")
+		return nil, initialCursor, false
+	}
+
+	table, newCursor, ok := p.parseQualifiedName(tokens, cursor, false)
+	if !ok {
 		p.helpMessage(tokens, cursor, "Expecte table name")
 		return nil, initialCursor, false
 	}
-	cursor = newCursor
+	cursor = newCursor
+
+	is := InsertStatement{
+		Table: table,
+	}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(leftParenSymbol))
+	if ok {
+		rightParenToken := tokenFromSymbol(rightParenSymbol)
+		cols, newCursor, ok := p.parseIdentifierList(tokens, cursor, rightParenToken)
+		if !ok {
+			return nil, initialCursor, false
+		}
+		is.Columns = cols
+		cursor = newCursor
+
+		_, cursor, ok = p.parseToken(tokens, cursor, rightParenToken)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected right paren")
+			return nil, initialCursor, false
+		}
+	}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(defaultKeyword))
+	if ok {
+		_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(valuesKeyword))
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected VALUES after DEFAULT")
+			return nil, initialCursor, false
+		}
+
+		is.DefaultValues = true
+	} else if query, newCursor, ok := p.parseSelectStatement(tokens, cursor, delimiter); ok {
+		is.Query = query
+		cursor = newCursor
+	} else {
+		_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(valuesKeyword))
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected VALUES, DEFAULT VALUES or SELECT")
+			return nil, initialCursor, false
+		}
+
+		values, newCursor, ok := p.parseValues(tokens, cursor, delimiter)
+		if !ok {
+			return nil, initialCursor, false
+		}
+		is.Values = values
+		cursor = newCursor
+	}
+
+	// the clauses parse the ON themselves, so that it's part of their span
+	_, _, ok = p.parseToken(tokens, cursor, tokenFromKeyword(onKeyword))
+	if ok {
+		if p.Dialect == MySQLDialect {
+			set, newCursor, ok := p.parseOnDuplicateKey(tokens, cursor, delimiter)
+			if !ok {
+				return nil, initialCursor, false
+			}
+			is.OnDuplicateKey = set
+			cursor = newCursor
+		} else {
+			onConflict, newCursor, ok := p.parseOnConflict(tokens, cursor, delimiter)
+			if !ok {
+				return nil, initialCursor, false
+			}
+			is.OnConflict = onConflict
+			cursor = newCursor
+		}
+	}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(returningKeyword))
+	if ok {
+		returning, newCursor, ok := p.parseReturning(tokens, cursor, delimiter)
+		if !ok {
+			return nil, initialCursor, false
+		}
+		is.Returning = returning
+		cursor = newCursor
+	}
+
+	is.span = spanOf(tokens, initialCursor, cursor)
+	return &is, cursor, true
+}
+
+func (p Parser) parseReturning(tokens []*token, initialCursor uint, delimiter token) ([]*SelectItem, uint, bool) {
+	returning, cursor, ok := p.parseSelectItem(tokens, initialCursor, []token{delimiter})
+	if !ok || len(returning) == 0 {
+		p.helpMessage(tokens, initialCursor, "Expected RETURNING items")
+		return nil, initialCursor, false
+	}
+
+	return returning, cursor, true
+}
+
+func (p Parser) parseUpdateStatement(tokens []*token, initialCursor uint, delimiter token) (*UpdateStatement, uint, bool) {
+	cursor := initialCursor
+	ok := false
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(updateKeyword))
+	if !ok {
+		return nil, initialCursor, false
+	}
+
+	table, newCursor, ok := p.parseQualifiedName(tokens, cursor, false)
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected table name")
+		return nil, initialCursor, false
+	}
+	cursor = newCursor
+
+	us := UpdateStatement{
+		Table: table,
+	}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(setKeyword))
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected SET")
+		return nil, initialCursor, false
+	}
+
+	whereToken := tokenFromKeyword(whereKeyword)
+	returningToken := tokenFromKeyword(returningKeyword)
+
+	set, newCursor, ok := p.parseSetClauses(tokens, cursor, []token{whereToken, returningToken, delimiter})
+	if !ok {
+		return nil, initialCursor, false
+	}
+	us.Set = set
+	cursor = newCursor
+
+	_, cursor, ok = p.parseToken(tokens, cursor, whereToken)
+	if ok {
+		where, newCursor, ok := p.parseExpression(tokens, cursor, []token{returningToken, delimiter}, 0)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected WHERE conditionals")
+			return nil, initialCursor, false
+		}
+
+		us.Where = where
+		cursor = newCursor
+	}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, returningToken)
+	if ok {
+		returning, newCursor, ok := p.parseReturning(tokens, cursor, delimiter)
+		if !ok {
+			return nil, initialCursor, false
+		}
+		us.Returning = returning
+		cursor = newCursor
+	}
+
+	us.span = spanOf(tokens, initialCursor, cursor)
+	return &us, cursor, true
+}
+
+func (p Parser) parseDeleteStatement(tokens []*token, initialCursor uint, delimiter token) (*DeleteStatement, uint, bool) {
+	cursor := initialCursor
+	ok := false
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(deleteKeyword))
+	if !ok {
+		return nil, initialCursor, false
+	}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(fromKeyword))
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected FROM")
+		return nil, initialCursor, false
+	}
+
+	table, newCursor, ok := p.parseQualifiedName(tokens, cursor, false)
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected table name")
+		return nil, initialCursor, false
+	}
+	cursor = newCursor
+
+	ds := DeleteStatement{
+		Table: table,
+	}
+
+	returningToken := tokenFromKeyword(returningKeyword)
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(whereKeyword))
+	if ok {
+		where, newCursor, ok := p.parseExpression(tokens, cursor, []token{returningToken, delimiter}, 0)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected WHERE conditionals")
+			return nil, initialCursor, false
+		}
+
+		ds.Where = where
+		cursor = newCursor
+	}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, returningToken)
+	if ok {
+		returning, newCursor, ok := p.parseReturning(tokens, cursor, delimiter)
+		if !ok {
+			return nil, initialCursor, false
+		}
+		ds.Returning = returning
+		cursor = newCursor
+	}
+
+	ds.span = spanOf(tokens, initialCursor, cursor)
+	return &ds, cursor, true
+}
+
+func (p Parser) parseSetClauses(tokens []*token, initialCursor uint, delimiters []token) ([]*SetClause, uint, bool) {
+	cursor := initialCursor
+
+	commaToken := tokenFromSymbol(commaSymbol)
+
+	var set []*SetClause
+	for {
+		if len(set) > 0 {
+			var ok bool
+			_, cursor, ok = p.parseToken(tokens, cursor, commaToken)
+			if !ok {
+				break
+			}
+		}
+
+		clauseCursor := cursor
+		col, newCursor, ok := p.parseIdentifier(tokens, cursor)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected column name")
+			return nil, initialCursor, false
+		}
+		cursor = newCursor
+
+		_, cursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(eqSymbol))
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected =")
+			return nil, initialCursor, false
+		}
+
+		exp, newCursor, ok := p.parseExpression(tokens, cursor, append([]token{commaToken}, delimiters...), 0)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected expression")
+			return nil, initialCursor, false
+		}
+		cursor = newCursor
+
+		set = append(set, &SetClause{
+			span:   spanOf(tokens, clauseCursor, cursor),
+			Column: col,
+			Value:  exp,
+		})
+	}
+
+	return set, cursor, true
+}
+
+func (p Parser) parseOnConflict(tokens []*token, initialCursor uint, delimiter token) (*OnConflictClause, uint, bool) {
+	cursor := initialCursor
+	ok := false
+
+	for _, k := range []keyword{onKeyword, conflictKeyword} {
+		_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(k))
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected ON CONFLICT")
+			return nil, initialCursor, false
+		}
+	}
+
+	occ := OnConflictClause{}
+
+	rightParenToken := tokenFromSymbol(rightParenSymbol)
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(leftParenSymbol))
+	if ok {
+		cols, newCursor, ok := p.parseIdentifierList(tokens, cursor, rightParenToken)
+		if !ok {
+			return nil, initialCursor, false
+		}
+		occ.Columns = cols
+		cursor = newCursor
+
+		_, cursor, ok = p.parseToken(tokens, cursor, rightParenToken)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected right paren")
+			return nil, initialCursor, false
+		}
+	} else if _, newCursor, ok := p.parseToken(tokens, cursor, tokenFromKeyword(onKeyword)); ok {
+		cursor = newCursor
+
+		_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(constraintKeyword))
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected CONSTRAINT after ON")
+			return nil, initialCursor, false
+		}
+
+		constraint, newCursor, ok := p.parseIdentifier(tokens, cursor)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected constraint name")
+			return nil, initialCursor, false
+		}
+		occ.Constraint = constraint
+		cursor = newCursor
+	}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(doKeyword))
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected DO")
+		return nil, initialCursor, false
+	}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(nothingKeyword))
+	if ok {
+		occ.DoNothing = true
+		occ.span = spanOf(tokens, initialCursor, cursor)
+		return &occ, cursor, true
+	}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(updateKeyword))
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected NOTHING or UPDATE after DO")
+		return nil, initialCursor, false
+	}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(setKeyword))
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected SET")
+		return nil, initialCursor, false
+	}
+
+	whereToken := tokenFromKeyword(whereKeyword)
+	returningToken := tokenFromKeyword(returningKeyword)
+
+	set, newCursor, ok := p.parseSetClauses(tokens, cursor, []token{whereToken, returningToken, delimiter})
+	if !ok {
+		return nil, initialCursor, false
+	}
+	occ.Set = set
+	cursor = newCursor
+
+	_, cursor, ok = p.parseToken(tokens, cursor, whereToken)
+	if ok {
+		where, newCursor, ok := p.parseExpression(tokens, cursor, []token{returningToken, delimiter}, 0)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected WHERE conditionals")
+			return nil, initialCursor, false
+		}
+
+		occ.Where = where
+		cursor = newCursor
+	}
+
+	occ.span = spanOf(tokens, initialCursor, cursor)
+	return &occ, cursor, true
+}
+
+func (p Parser) parseOnDuplicateKey(tokens []*token, initialCursor uint, delimiter token) ([]*SetClause, uint, bool) {
+	cursor := initialCursor
+
+	for _, k := range []keyword{onKeyword, duplicateKeyword, keyKeyword, updateKeyword} {
+		var ok bool
+		_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(k))
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected ON DUPLICATE KEY UPDATE")
+			return nil, initialCursor, false
+		}
+	}
+
+	return p.parseSetClauses(tokens, cursor, []token{tokenFromKeyword(returningKeyword), delimiter})
+}
+
+// parseAutoIncrement parses MySQL's AUTO_INCREMENT, SQLite's AUTOINCREMENT
+// and the standard GENERATED BY DEFAULT AS IDENTITY
+func (p Parser) parseAutoIncrement(tokens []*token, initialCursor uint) (uint, bool) {
+	for _, k := range []keyword{autoIncrementKeyword, autoincrementKeyword} {
+		if _, cursor, ok := p.parseToken(tokens, initialCursor, tokenFromKeyword(k)); ok {
+			return cursor, true
+		}
+	}
+
+	cursor := initialCursor
+	identity := []keyword{generatedKeyword, byKeyword, defaultKeyword, asKeyword, identityKeyword}
+	for _, k := range identity {
+		var ok bool
+		_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(k))
+		if !ok {
+			return initialCursor, false
+		}
+	}
+
+	return cursor, true
+}
+
+func (p Parser) parseColumnDefinitions(tokens []*token, initialCursor uint, delimiter token) ([]*ColumnDefinition, uint, bool) {
+	cursor := initialCursor
+
+	var cds []*ColumnDefinition
+	for {
+		if cursor >= uint(len(tokens)) {
+			return nil, initialCursor, false
+		}
+
+		current := tokens[cursor]
+		if delimiter.equals(current) {
+			break
+		}
+
+		if len(cds) > 0 {
+			var ok bool
+			_, cursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(commaSymbol))
+			if !ok {
+				p.helpMessage(tokens, cursor, "Expected comma")
+				return nil, initialCursor, false
+			}
+		}
+
+		columnCursor := cursor
+		id, newCursor, ok := p.parseIdentifier(tokens, cursor)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected column name")
+			return nil, initialCursor, false
+		}
+		cursor = newCursor
+
+		ty, newCursor, ok := p.parseTokenKind(tokens, cursor, keywordKind)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected column type")
+			return nil, initialCursor, false
+		}
+		cursor = newCursor
+
+		cd := ColumnDefinition{
+			Name:     id,
+			DataType: strings.ToUpper(ty.value),
+		}
+
+		// PostgreSQL's serial is an auto incremented int
+		if keyword(ty.value) == serialKeyword {
+			cd.DataType = strings.ToUpper(string(intKeyword))
+			cd.AutoIncrement = true
+		}
+
+		for {
+			_, newCursor, ok := p.parseToken(tokens, cursor, tokenFromKeyword(primarykeyKeyword))
+			if ok {
+				cd.PrimaryKey = true
+				cursor = newCursor
+				continue
+			}
+
+			newCursor, ok = p.parseAutoIncrement(tokens, cursor)
+			if ok {
+				cd.AutoIncrement = true
+				cursor = newCursor
+				continue
+			}
+
+			break
+		}
+
+		cd.span = spanOf(tokens, columnCursor, cursor)
+		cds = append(cds, &cd)
+	}
+
+	return cds, cursor, true
+}
+
+func (p Parser) parseCreateTableStatement(tokens []*token, initialCursor uint, _ token) (*CreateTableStatement, uint, bool) {
+	cursor := initialCursor
+	ok := false
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(createKeyword))
+	if !ok {
+		return nil, initialCursor, false
+	}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(tableKeyword))
+	if !ok {
+		return nil, initialCursor, false
+	}
+
+	name, newCursor, ok := p.parseQualifiedName(tokens, cursor, false)
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected table name")
+		return nil, initialCursor, false
+	}
+	cursor = newCursor
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(leftParenSymbol))
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected left parenthesis")
+		return nil, initialCursor, false
+	}
+
+	cols, newCursor, ok := p.parseColumnDefinitions(tokens, cursor, tokenFromSymbol(rightParenSymbol))
+	if !ok {
+		return nil, initialCursor, false
+	}
+	cursor = newCursor
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(rightParenSymbol))
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected right parenthesis")
+		return nil, initialCursor, false
+	}
+
+	return &CreateTableStatement{
+		span:    spanOf(tokens, initialCursor, cursor),
+		Name:    name,
+		Columns: cols,
+	}, cursor, true
+}
+
+func (p Parser) parseDropTableStatement(tokens []*token, initialCursor uint, _ token) (*DropTableStatement, uint, bool) {
+	cursor := initialCursor
+	ok := false
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(dropKeyword))
+	if !ok {
+		return nil, initialCursor, false
+	}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(tableKeyword))
+	if !ok {
+		return nil, initialCursor, false
+	}
+
+	name, newCursor, ok := p.parseQualifiedName(tokens, cursor, false)
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected table name")
+		return nil, initialCursor, false
+	}
+	cursor = newCursor
+
+	return &DropTableStatement{
+		span: spanOf(tokens, initialCursor, cursor),
+		Name: name,
+	}, cursor, true
+}
+
+func (p Parser) parseIdentifierList(tokens []*token, initialCursor uint, delimiter token) ([]*Identifier, uint, bool) {
+	cursor := initialCursor
+
+	var ids []*Identifier
+	for {
+		if cursor >= uint(len(tokens)) {
+			return nil, initialCursor, false
+		}
+
+		current := tokens[cursor]
+		if delimiter.equals(current) {
+			break
+		}
+
+		if len(ids) > 0 {
+			var ok bool
+			_, cursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(commaSymbol))
+			if !ok {
+				p.helpMessage(tokens, cursor, "Expected comma")
+				return nil, initialCursor, false
+			}
+		}
+
+		id, newCursor, ok := p.parseIdentifier(tokens, cursor)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected identifier")
+			return nil, initialCursor, false
+		}
+		cursor = newCursor
+
+		ids = append(ids, id)
+	}
+
+	if len(ids) == 0 {
+		p.helpMessage(tokens, cursor, "Expected identifier")
+		return nil, initialCursor, false
+	}
+
+	return ids, cursor, true
+}
+
+func (p Parser) parseIndexElements(tokens []*token, initialCursor uint, delimiter token) ([]*IndexElement, uint, bool) {
+	cursor := initialCursor
+
+	commaToken := tokenFromSymbol(commaSymbol)
+	ascToken := tokenFromKeyword(ascKeyword)
+	descToken := tokenFromKeyword(descKeyword)
+	nullsToken := tokenFromKeyword(nullsKeyword)
+
+	var elements []*IndexElement
+	for {
+		if cursor >= uint(len(tokens)) {
+			return nil, initialCursor, false
+		}
+
+		current := tokens[cursor]
+		if delimiter.equals(current) {
+			break
+		}
+
+		if len(elements) > 0 {
+			var ok bool
+			_, cursor, ok = p.parseToken(tokens, cursor, commaToken)
+			if !ok {
+				p.helpMessage(tokens, cursor, "Expected comma")
+				return nil, initialCursor, false
+			}
+		}
+
+		elementCursor := cursor
+		delimiters := []token{commaToken, delimiter, ascToken, descToken, nullsToken}
+		exp, newCursor, ok := p.parseExpression(tokens, cursor, delimiters, 0)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected index expression")
+			return nil, initialCursor, false
+		}
+		cursor = newCursor
+
+		ie := IndexElement{Expression: exp}
+		for _, o := range []token{ascToken, descToken} {
+			order, newCursor, ok := p.parseToken(tokens, cursor, o)
+			if ok {
+				ie.Order = strings.ToUpper(order.value)
+				cursor = newCursor
+				break
+			}
+		}
+
+		_, cursor, ok = p.parseToken(tokens, cursor, nullsToken)
+		if ok {
+			for _, n := range []token{tokenFromKeyword(firstKeyword), tokenFromKeyword(lastKeyword)} {
+				nulls, newCursor, ok := p.parseToken(tokens, cursor, n)
+				if ok {
+					ie.Nulls = strings.ToUpper(nulls.value)
+					cursor = newCursor
+					break
+				}
+			}
+
+			if ie.Nulls == "" {
+				p.helpMessage(tokens, cursor, "Expected FIRST or LAST after NULLS")
+				return nil, initialCursor, false
+			}
+		}
+
+		ie.span = spanOf(tokens, elementCursor, cursor)
+		elements = append(elements, &ie)
+	}
+
+	if len(elements) == 0 {
+		p.helpMessage(tokens, cursor, "Expected index expression")
+		return nil, initialCursor, false
+	}
+
+	return elements, cursor, true
+}
+
+func (p Parser) parseCreateIndexStatement(tokens []*token, initialCursor uint, delimiter token) (*CreateIndexStatement, uint, bool) {
+	cursor := initialCursor
+	ok := false
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(createKeyword))
+	if !ok {
+		return nil, initialCursor, false
+	}
+
+	unique := false
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(uniqueKeyword))
+	if ok {
+		unique = true
+	}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(indexKeyword))
+	if !ok {
+		return nil, initialCursor, false
+	}
+
+	name, newCursor, ok := p.parseIdentifier(tokens, cursor)
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected index name")
+		return nil, initialCursor, false
+	}
+	cursor = newCursor
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(onKeyword))
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected ON keyword")
+		return nil, initialCursor, false
+	}
+
+	table, newCursor, ok := p.parseQualifiedName(tokens, cursor, false)
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected table name")
+		return nil, initialCursor, false
+	}
+	cursor = newCursor
+
+	cis := CreateIndexStatement{
+		Name:   name,
+		Unique: unique,
+		Table:  table,
+	}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(usingKeyword))
+	if ok {
+		method, newCursor, ok := p.parseIdentifier(tokens, cursor)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected index method after USING")
+			return nil, initialCursor, false
+		}
+
+		cis.Method = method
+		cursor = newCursor
+	}
+
+	rightParenToken := tokenFromSymbol(rightParenSymbol)
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(leftParenSymbol))
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected left parenthesis")
+		return nil, initialCursor, false
+	}
+
+	elements, newCursor, ok := p.parseIndexElements(tokens, cursor, rightParenToken)
+	if !ok {
+		return nil, initialCursor, false
+	}
+	cis.Elements = elements
+	cursor = newCursor
+
+	_, cursor, ok = p.parseToken(tokens, cursor, rightParenToken)
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected right parenthesis")
+		return nil, initialCursor, false
+	}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(includeKeyword))
+	if ok {
+		_, cursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(leftParenSymbol))
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected left parenthesis after INCLUDE")
+			return nil, initialCursor, false
+		}
+
+		include, newCursor, ok := p.parseIdentifierList(tokens, cursor, rightParenToken)
+		if !ok {
+			return nil, initialCursor, false
+		}
+		cis.Include = include
+		cursor = newCursor
+
+		_, cursor, ok = p.parseToken(tokens, cursor, rightParenToken)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected right parenthesis")
+			return nil, initialCursor, false
+		}
+	}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(whereKeyword))
+	if ok {
+		where, newCursor, ok := p.parseExpression(tokens, cursor, []token{delimiter}, 0)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected WHERE conditionals")
+			return nil, initialCursor, false
+		}
+
+		cis.Where = where
+		cursor = newCursor
+	}
+
+	cis.span = spanOf(tokens, initialCursor, cursor)
+	return &cis, cursor, true
+}
 
-	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(valuesKeyword))
+func (p Parser) parseDropIndexStatement(tokens []*token, initialCursor uint, _ token) (*DropIndexStatement, uint, bool) {
+	cursor := initialCursor
+	ok := false
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(dropKeyword))
 	if !ok {
-		p.helpMessage(tokens, cursor, "Expected left paren")
 		return nil, initialCursor, false
 	}
 
-	values, newCursor, ok := p.parseExpressions(tokens, cursor, tokenFromSymbol(rightParenSymbol))
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(indexKeyword))
 	if !ok {
-		p.helpMessage(tokens, cursor, "Expected expressions")
 		return nil, initialCursor, false
 	}
-	cursor = newCursor
 
-	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(rightParenSymbol))
+	name, newCursor, ok := p.parseQualifiedName(tokens, cursor, false)
 	if !ok {
-		p.helpMessage(tokens, cursor, "Expected right paren")
+		p.helpMessage(tokens, cursor, "Expected index name")
 		return nil, initialCursor, false
 	}
+	cursor = newCursor
 
-	return &InsertStatement{
-		table:  *table,
-		values: values,
+	return &DropIndexStatement{
+		span: spanOf(tokens, initialCursor, cursor),
+		Name: name,
 	}, cursor, true
 }
 
-func (p Parser) parseColumnDefinitions(tokens []*token, initialCursor uint, delimiter token) (*[]*columnDefinition, uint, bool) {
+func (p Parser) parseCreateViewStatement(tokens []*token, initialCursor uint, delimiter token) (*CreateViewStatement, uint, bool) {
 	cursor := initialCursor
+	ok := false
 
-	var cds []*columnDefinition
-	for {
-		if cursor >= uint(len(tokens)) {
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(createKeyword))
+	if !ok {
+		return nil, initialCursor, false
+	}
+
+	cvs := CreateViewStatement{}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(orKeyword))
+	if ok {
+		_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(replaceKeyword))
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected REPLACE after OR")
 			return nil, initialCursor, false
 		}
 
-		current := tokens[cursor]
-		if delimiter.equals(current) {
-			break
-		}
+		cvs.OrReplace = true
+	}
 
-		if len(cds) > 0 {
-			var ok bool
-			_, cursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(commaSymbol))
-			if !ok {
-				p.helpMessage(tokens, cursor, "Expected comma")
-				return nil, initialCursor, false
-			}
-		}
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(materializedKeyword))
+	if ok {
+		cvs.Materialized = true
+	}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(viewKeyword))
+	if !ok {
+		return nil, initialCursor, false
+	}
+
+	name, newCursor, ok := p.parseQualifiedName(tokens, cursor, false)
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected view name")
+		return nil, initialCursor, false
+	}
+	cvs.Name = name
+	cursor = newCursor
 
-		id, newCursor, ok := p.parseTokenKind(tokens, cursor, identifierKind)
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(leftParenSymbol))
+	if ok {
+		rightParenToken := tokenFromSymbol(rightParenSymbol)
+		cols, newCursor, ok := p.parseIdentifierList(tokens, cursor, rightParenToken)
 		if !ok {
-			p.helpMessage(tokens, cursor, "Expected column name")
 			return nil, initialCursor, false
 		}
+		cvs.Columns = cols
 		cursor = newCursor
 
-		ty, newCursor, ok := p.parseTokenKind(tokens, cursor, keywordKind)
+		_, cursor, ok = p.parseToken(tokens, cursor, rightParenToken)
 		if !ok {
-			p.helpMessage(tokens, cursor, "Expected column type")
+			p.helpMessage(tokens, cursor, "Expected right parenthesis")
 			return nil, initialCursor, false
 		}
-		cursor = newCursor
+	}
 
-		primaryKey := false
-		_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(primarykeyKeyword))
-		if ok {
-			primaryKey = true
-		}
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(asKeyword))
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected AS keyword")
+		return nil, initialCursor, false
+	}
 
-		cds = append(cds, &columnDefinition{
-			name:       *id,
-			datatype:   *ty,
-			primaryKey: primaryKey,
-		})
+	query, newCursor, ok := p.parseSelectStatement(tokens, cursor, delimiter)
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected select statement")
+		return nil, initialCursor, false
 	}
+	cvs.Query = query
+	cursor = newCursor
 
-	return &cds, cursor, true
+	cvs.span = spanOf(tokens, initialCursor, cursor)
+	return &cvs, cursor, true
 }
 
-func (p Parser) parseCreateTableStatement(tokens []*token, initialCursor uint, _ token) (*CreateTableStatement, uint, bool) {
+func (p Parser) parseRefreshMaterializedViewStatement(tokens []*token, initialCursor uint, _ token) (*RefreshMaterializedViewStatement, uint, bool) {
 	cursor := initialCursor
 	ok := false
 
-	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(createKeyword))
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(refreshKeyword))
 	if !ok {
 		return nil, initialCursor, false
 	}
 
-	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(tableKeyword))
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(materializedKeyword))
 	if !ok {
+		p.helpMessage(tokens, cursor, "Expected MATERIALIZED keyword")
 		return nil, initialCursor, false
 	}
 
-	name, newCursor, ok := p.parseTokenKind(tokens, cursor, identifierKind)
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(viewKeyword))
 	if !ok {
-		p.helpMessage(tokens, cursor, "Expected table name")
+		p.helpMessage(tokens, cursor, "Expected VIEW keyword")
+		return nil, initialCursor, false
+	}
+
+	name, newCursor, ok := p.parseQualifiedName(tokens, cursor, false)
+	if !ok {
+		p.helpMessage(tokens, cursor, "Expected view name")
 		return nil, initialCursor, false
 	}
 	cursor = newCursor
 
-	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(leftParenSymbol))
+	return &RefreshMaterializedViewStatement{
+		span: spanOf(tokens, initialCursor, cursor),
+		Name: name,
+	}, cursor, true
+}
+
+func (p Parser) parseDropViewStatement(tokens []*token, initialCursor uint, _ token) (*DropViewStatement, uint, bool) {
+	cursor := initialCursor
+	ok := false
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(dropKeyword))
 	if !ok {
-		p.helpMessage(tokens, cursor, "Expected left parenthesis")
 		return nil, initialCursor, false
 	}
 
-	cols, newCursor, ok := p.parseColumnDefinitions(tokens, cursor, tokenFromSymbol(rightParenSymbol))
+	materialized := false
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(materializedKeyword))
+	if ok {
+		materialized = true
+	}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(viewKeyword))
 	if !ok {
 		return nil, initialCursor, false
 	}
-	cursor = newCursor
 
-	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromSymbol(rightParenSymbol))
+	name, newCursor, ok := p.parseQualifiedName(tokens, cursor, false)
 	if !ok {
-		p.helpMessage(tokens, cursor, "Expected right parenthesis")
+		p.helpMessage(tokens, cursor, "Expected view name")
 		return nil, initialCursor, false
 	}
+	cursor = newCursor
 
-	return &CreateTableStatement{
-		name: *name,
-		cols: cols,
+	return &DropViewStatement{
+		span:         spanOf(tokens, initialCursor, cursor),
+		Materialized: materialized,
+		Name:         name,
 	}, cursor, true
 }
 
-func (p Parser) parseDropTableStatement(tokens []*token, initialCursor uint, _ token) (*DropTableStatement, uint, bool) {
+func (p Parser) parseTransactionModes(tokens []*token, initialCursor uint, bs *BeginStatement) (uint, bool) {
+	cursor := initialCursor
+	readToken := tokenFromKeyword(readKeyword)
+
+	for {
+		var ok bool
+		if bs.IsolationLevel != "" || bs.AccessMode != "" {
+			// modes may optionally be separated by commas
+			_, cursor, _ = p.parseToken(tokens, cursor, tokenFromSymbol(commaSymbol))
+		}
+
+		_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(isolationKeyword))
+		if ok {
+			_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(levelKeyword))
+			if !ok {
+				p.helpMessage(tokens, cursor, "Expected LEVEL after ISOLATION")
+				return initialCursor, false
+			}
+
+			levels := [][]keyword{
+				{serializableKeyword},
+				{repeatableKeyword, readKeyword},
+				{readKeyword, committedKeyword},
+				{readKeyword, uncommittedKeyword},
+			}
+
+			isolationLevel := ""
+		level:
+			for _, level := range levels {
+				levelCursor := cursor
+				var words []string
+				for _, k := range level {
+					var t *token
+					t, levelCursor, ok = p.parseToken(tokens, levelCursor, tokenFromKeyword(k))
+					if !ok {
+						continue level
+					}
+					words = append(words, strings.ToUpper(t.value))
+				}
+
+				isolationLevel = strings.Join(words, " ")
+				cursor = levelCursor
+				break
+			}
+
+			if isolationLevel == "" {
+				p.helpMessage(tokens, cursor, "Expected isolation level")
+				return initialCursor, false
+			}
+
+			bs.IsolationLevel = isolationLevel
+
+			continue
+		}
+
+		_, newCursor, ok := p.parseToken(tokens, cursor, readToken)
+		if !ok {
+			break
+		}
+
+		var mode *token
+		for _, k := range []keyword{onlyKeyword, writeKeyword} {
+			mode, cursor, ok = p.parseToken(tokens, newCursor, tokenFromKeyword(k))
+			if ok {
+				break
+			}
+		}
+
+		if mode == nil {
+			p.helpMessage(tokens, cursor, "Expected ONLY or WRITE after READ")
+			return initialCursor, false
+		}
+
+		bs.AccessMode = "READ " + strings.ToUpper(mode.value)
+	}
+
+	return cursor, true
+}
+
+func (p Parser) parseBeginStatement(tokens []*token, initialCursor uint, _ token) (*BeginStatement, uint, bool) {
 	cursor := initialCursor
 	ok := false
 
-	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(dropKeyword))
-	if !ok {
-		return nil, initialCursor, false
+	transactionToken := tokenFromKeyword(transactionKeyword)
+	bs := BeginStatement{}
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(beginKeyword))
+	if ok {
+		_, cursor, ok = p.parseToken(tokens, cursor, transactionToken)
+		bs.Transaction = ok
+	} else {
+		_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(startKeyword))
+		if !ok {
+			return nil, initialCursor, false
+		}
+
+		_, cursor, ok = p.parseToken(tokens, cursor, transactionToken)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected TRANSACTION after START")
+			return nil, initialCursor, false
+		}
+
+		bs.Start = true
 	}
 
-	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(tableKeyword))
+	cursor, ok = p.parseTransactionModes(tokens, cursor, &bs)
 	if !ok {
 		return nil, initialCursor, false
 	}
 
-	name, newCursor, ok := p.parseTokenKind(tokens, cursor, identifierKind)
+	bs.span = spanOf(tokens, initialCursor, cursor)
+	return &bs, cursor, true
+}
+
+func (p Parser) parseCommitStatement(tokens []*token, initialCursor uint, _ token) (*CommitStatement, uint, bool) {
+	cursor := initialCursor
+	ok := false
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(commitKeyword))
 	if !ok {
-		p.helpMessage(tokens, cursor, "Expected table name")
 		return nil, initialCursor, false
 	}
-	cursor = newCursor
 
-	return &DropTableStatement{
-		name: *name,
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(transactionKeyword))
+
+	return &CommitStatement{
+		span:        spanOf(tokens, initialCursor, cursor),
+		Transaction: ok,
 	}, cursor, true
 }
 
-func (p Parser) parseCreateIndexStatement(tokens []*token, initialCursor uint, delimiter token) (*CreateIndexStatement, uint, bool) {
+func (p Parser) parseRollbackStatement(tokens []*token, initialCursor uint, _ token) (*RollbackStatement, uint, bool) {
 	cursor := initialCursor
 	ok := false
 
-	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(createKeyword))
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(rollbackKeyword))
 	if !ok {
 		return nil, initialCursor, false
 	}
 
-	unique := false
-	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(uniqueKeyword))
+	rs := RollbackStatement{}
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(transactionKeyword))
+	rs.Transaction = ok
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(toKeyword))
 	if ok {
-		unique = true
+		// the SAVEPOINT keyword itself is optional
+		_, cursor, _ = p.parseToken(tokens, cursor, tokenFromKeyword(savepointKeyword))
+
+		name, newCursor, ok := p.parseIdentifier(tokens, cursor)
+		if !ok {
+			p.helpMessage(tokens, cursor, "Expected savepoint name")
+			return nil, initialCursor, false
+		}
+
+		rs.Savepoint = name
+		cursor = newCursor
 	}
 
-	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(indexKeyword))
+	rs.span = spanOf(tokens, initialCursor, cursor)
+	return &rs, cursor, true
+}
+
+func (p Parser) parseSavepointStatement(tokens []*token, initialCursor uint, _ token) (*SavepointStatement, uint, bool) {
+	cursor := initialCursor
+	ok := false
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(savepointKeyword))
 	if !ok {
 		return nil, initialCursor, false
 	}
 
-	name, newCursor, ok := p.parseTokenKind(tokens, cursor, identifierKind)
+	name, newCursor, ok := p.parseIdentifier(tokens, cursor)
 	if !ok {
-		p.helpMessage(tokens, cursor, "Expected index name")
+		p.helpMessage(tokens, cursor, "Expected savepoint name")
 		return nil, initialCursor, false
 	}
 	cursor = newCursor
 
-	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(onKeyword))
-	if !ok {
-		p.helpMessage(tokens, cursor, "Expected ON keyword")
-		return nil, initialCursor, false
-	}
+	return &SavepointStatement{
+		span: spanOf(tokens, initialCursor, cursor),
+		Name: name,
+	}, cursor, true
+}
 
-	table, newCursor, ok := p.parseTokenKind(tokens, cursor, identifierKind)
+func (p Parser) parseReleaseSavepointStatement(tokens []*token, initialCursor uint, _ token) (*ReleaseSavepointStatement, uint, bool) {
+	cursor := initialCursor
+	ok := false
+
+	_, cursor, ok = p.parseToken(tokens, cursor, tokenFromKeyword(releaseKeyword))
 	if !ok {
-		p.helpMessage(tokens, cursor, "Expected table name")
 		return nil, initialCursor, false
 	}
-	cursor = newCursor
 
-	e, newCursor, ok := p.parseExpression(tokens, cursor, []token{delimiter}, 0)
+	// the SAVEPOINT keyword itself is optional
+	_, cursor, _ = p.parseToken(tokens, cursor, tokenFromKeyword(savepointKeyword))
+
+	name, newCursor, ok := p.parseIdentifier(tokens, cursor)
 	if !ok {
-		p.helpMessage(tokens, cursor, "Expected table name")
+		p.helpMessage(tokens, cursor, "Expected savepoint name")
 		return nil, initialCursor, false
 	}
 	cursor = newCursor
 
-	return &CreateIndexStatement{
-		name:   *name,
-		unique: unique,
-		table:  *table,
-		exp:    *e,
+	return &ReleaseSavepointStatement{
+		span: spanOf(tokens, initialCursor, cursor),
+		Name: name,
 	}, cursor, true
 }
 
@@ -564,6 +2083,22 @@ func (p Parser) parseStatement(tokens []*token, initialCursor uint, _ token) (*S
 		}, newCursor, true
 	}
 
+	upd, newCursor, ok := p.parseUpdateStatement(tokens, cursor, semicolonToken)
+	if ok {
+		return &Statement{
+			Kind:            UpdateKind,
+			UpdateStatement: upd,
+		}, newCursor, true
+	}
+
+	del, newCursor, ok := p.parseDeleteStatement(tokens, cursor, semicolonToken)
+	if ok {
+		return &Statement{
+			Kind:            DeleteKind,
+			DeleteStatement: del,
+		}, newCursor, true
+	}
+
 	crtTbl, newCursor, ok := p.parseCreateTableStatement(tokens, cursor, semicolonToken)
 	if ok {
 		return &Statement{
@@ -588,18 +2123,93 @@ func (p Parser) parseStatement(tokens []*token, initialCursor uint, _ token) (*S
 		}, newCursor, true
 	}
 
+	dpIdx, newCursor, ok := p.parseDropIndexStatement(tokens, cursor, semicolonToken)
+	if ok {
+		return &Statement{
+			Kind:               DropIndexKind,
+			DropIndexStatement: dpIdx,
+		}, newCursor, true
+	}
+
+	crtView, newCursor, ok := p.parseCreateViewStatement(tokens, cursor, semicolonToken)
+	if ok {
+		return &Statement{
+			Kind:                CreateViewKind,
+			CreateViewStatement: crtView,
+		}, newCursor, true
+	}
+
+	rfView, newCursor, ok := p.parseRefreshMaterializedViewStatement(tokens, cursor, semicolonToken)
+	if ok {
+		return &Statement{
+			Kind:                             RefreshMaterializedViewKind,
+			RefreshMaterializedViewStatement: rfView,
+		}, newCursor, true
+	}
+
+	dpView, newCursor, ok := p.parseDropViewStatement(tokens, cursor, semicolonToken)
+	if ok {
+		return &Statement{
+			Kind:              DropViewKind,
+			DropViewStatement: dpView,
+		}, newCursor, true
+	}
+
+	begin, newCursor, ok := p.parseBeginStatement(tokens, cursor, semicolonToken)
+	if ok {
+		return &Statement{
+			Kind:           BeginKind,
+			BeginStatement: begin,
+		}, newCursor, true
+	}
+
+	commit, newCursor, ok := p.parseCommitStatement(tokens, cursor, semicolonToken)
+	if ok {
+		return &Statement{
+			Kind:            CommitKind,
+			CommitStatement: commit,
+		}, newCursor, true
+	}
+
+	rollback, newCursor, ok := p.parseRollbackStatement(tokens, cursor, semicolonToken)
+	if ok {
+		return &Statement{
+			Kind:              RollbackKind,
+			RollbackStatement: rollback,
+		}, newCursor, true
+	}
+
+	savepoint, newCursor, ok := p.parseSavepointStatement(tokens, cursor, semicolonToken)
+	if ok {
+		return &Statement{
+			Kind:               SavepointKind,
+			SavepointStatement: savepoint,
+		}, newCursor, true
+	}
+
+	release, newCursor, ok := p.parseReleaseSavepointStatement(tokens, cursor, semicolonToken)
+	if ok {
+		return &Statement{
+			Kind:                      ReleaseSavepointKind,
+			ReleaseSavepointStatement: release,
+		}, newCursor, true
+	}
+
 	return nil, initialCursor, false
 }
 
 // Parse is used to parse SQL syntx
 func (p Parser) Parse(source string) (*Ast, error) {
-	tokens, err := lex(source)
+	tokens, err := lexWithOptions(source, lexOptions{keepComments: p.KeepComments, dialect: p.Dialect})
 	if err != nil {
 		return nil, err
 	}
 
 	semicolonToken := tokenFromSymbol(semicolonSymbol)
 	if len(tokens) > 0 && !tokens[len(tokens)-1].equals(&semicolonToken) {
+		// comments after the last statement trail the semicolon it now ends with
+		last := tokens[len(tokens)-1]
+		semicolonToken.trailing, last.trailing = last.trailing, nil
 		tokens = append(tokens, &semicolonToken)
 	}
 
@@ -611,6 +2221,7 @@ func (p Parser) Parse(source string) (*Ast, error) {
 			p.helpMessage(tokens, cursor, "Expected statement")
 			return nil, errors.New("Failed to parse, expected statement")
 		}
+		start := cursor
 		cursor = newCursor
 
 		a.Statements = append(a.Statements, stmt)
@@ -629,6 +2240,12 @@ func (p Parser) Parse(source string) (*Ast, error) {
 			p.helpMessage(tokens, cursor, "Expected semi-colon delimiter between statements")
 			return nil, errors.New("Missing semi colon between statements")
 		}
+
+		if p.KeepComments {
+			stmt.tokens = tokens[start:cursor]
+			stmt.LeadingComments = tokens[start].leading
+			stmt.TrailingComments = tokens[cursor-1].trailing
+		}
 	}
 
 	return &a, nil
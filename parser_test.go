@@ -8,45 +8,52 @@ import (
 )
 
 func TestParseExpression(t *testing.T) {
+	// span of single line sources from the byte offsets
+	at := func(start, end uint) span {
+		return span{pos: Pos{start, 1, start + 1}, end: Pos{end, 1, end + 1}}
+	}
+
 	tests := []struct {
 		source string
-		ast    *expression
+		ast    Expression
 	}{
 		{
 			source: "2 = 3 AND 4 = 5",
-			ast: &expression{
-				binary: &binaryExpression{
-					a: expression{
-						binary: &binaryExpression{
-							a: expression{
-								literal: &token{"2", numericKind, location{0, 0}},
-								kind:    literalKind,
-							},
-							b: expression{
-								literal: &token{"3", numericKind, location{0, 5}},
-								kind:    literalKind,
-							},
-							op: token{"=", symbolKind, location{0, 3}},
-						},
-						kind: binaryKind,
-					},
-					b: expression{
-						binary: &binaryExpression{
-							a: expression{
-								literal: &token{"4", numericKind, location{0, 12}},
-								kind:    literalKind,
-							},
-							b: expression{
-								literal: &token{"5", numericKind, location{0, 17}},
-								kind:    literalKind,
-							},
-							op: token{"=", symbolKind, location{0, 15}},
+			ast: &BinaryExpression{
+				span: at(0, 15),
+				Left: &BinaryExpression{
+					span:     at(0, 5),
+					Left:     &Literal{span: at(0, 1), Kind: NumericLiteral, Value: "2"},
+					Right:    &Literal{span: at(4, 5), Kind: NumericLiteral, Value: "3"},
+					Operator: "=",
+				},
+				Right: &BinaryExpression{
+					span:     at(10, 15),
+					Left:     &Literal{span: at(10, 11), Kind: NumericLiteral, Value: "4"},
+					Right:    &Literal{span: at(14, 15), Kind: NumericLiteral, Value: "5"},
+					Operator: "=",
+				},
+				Operator: "and",
+			},
+		},
+		{
+			source: "(users.id || $1) <> 'x'",
+			ast: &BinaryExpression{
+				span: at(0, 23),
+				Left: &BinaryExpression{
+					span: at(1, 15),
+					Left: &QualifiedName{
+						span: at(1, 9),
+						Parts: []*Identifier{
+							{span: at(1, 6), Name: "users"},
+							{span: at(7, 9), Name: "id"},
 						},
-						kind: binaryKind,
 					},
-					op: token{"and", keywordKind, location{0, 8}},
+					Right:    &ParameterExpression{span: at(13, 15), Placeholder: "$1"},
+					Operator: "||",
 				},
-				kind: binaryKind,
+				Right:    &Literal{span: at(20, 23), Kind: StringLiteral, Value: "x"},
+				Operator: "<>",
 			},
 		},
 	}
@@ -60,6 +67,578 @@ func TestParseExpression(t *testing.T) {
 		ast, cursor, ok := parser.parseExpression(tokens, 0, []token{}, 0)
 		assert.True(t, ok, err, test.source)
 		assert.Equal(t, cursor, uint(len(tokens)))
-		assert.Equal(t, ast, test.ast, test.source)
+		assert.Equal(t, test.ast, ast, test.source)
+	}
+}
+
+func TestParser_Parse(t *testing.T) {
+	tests := []struct {
+		source  string
+		dialect Dialect
+		result  string
+	}{
+		{
+			source: "CREATE INDEX age_idx ON users (age)",
+			result: `CREATE INDEX age_idx ON users (age);`,
+		},
+		{
+			source: "CREATE UNIQUE INDEX name_idx ON users USING btree (last_name, age DESC NULLS FIRST, (age + 1) ASC) INCLUDE (id, email) WHERE active = true",
+			result: `CREATE UNIQUE INDEX name_idx ON users USING btree (last_name, age DESC NULLS FIRST, (age + 1) ASC) INCLUDE (id, email) WHERE (active = true);`,
+		},
+		{
+			source: "DROP INDEX age_idx",
+			result: `DROP INDEX age_idx;`,
+		},
+		{
+			source: "SELECT first, last || nulls AS include",
+			result: "SELECT\n\t\"first\",\n\t(\"last\" || \"nulls\") AS \"include\";",
+		},
+		{
+			source: "CREATE TABLE events (desc TEXT, using INT)",
+			result: "CREATE TABLE events (\n\t\"desc\" TEXT,\n\t\"using\" INT\n);",
+		},
+		{
+			source: "CREATE INDEX desc_idx ON events (desc DESC NULLS LAST)",
+			result: `CREATE INDEX desc_idx ON events ("desc" DESC NULLS LAST);`,
+		},
+		{
+			source: "SELECT id FROM users WHERE active = true",
+			result: `SELECT
+	id
+FROM
+	users
+WHERE
+	(active = true);`,
+		},
+		{
+			source: "CREATE VIEW active_users AS SELECT id, name FROM users WHERE active = true",
+			result: `CREATE VIEW active_users AS
+SELECT
+	id,
+	name
+FROM
+	users
+WHERE
+	(active = true);`,
+		},
+		{
+			source: "create or replace view v (a) as select id from users",
+			result: `CREATE OR REPLACE VIEW v (a) AS
+SELECT
+	id
+FROM
+	users;`,
+		},
+		{
+			source: "CREATE MATERIALIZED VIEW totals AS SELECT *",
+			result: `CREATE MATERIALIZED VIEW totals AS
+SELECT
+	*;`,
+		},
+		{
+			source: "REFRESH MATERIALIZED VIEW totals",
+			result: `REFRESH MATERIALIZED VIEW totals;`,
+		},
+		{
+			source: "DROP VIEW active_users",
+			result: `DROP VIEW active_users;`,
+		},
+		{
+			source: "DROP MATERIALIZED VIEW totals",
+			result: `DROP MATERIALIZED VIEW totals;`,
+		},
+		{
+			source: "INSERT INTO users VALUES (1, 'ann', true)",
+			result: `INSERT INTO users VALUES (1, 'ann', true);`,
+		},
+		{
+			source: "insert into users (id, name) values (1, 'ann'), (2, default), ((1 + 2), 'c')",
+			result: `INSERT INTO users (id, name) VALUES (1, 'ann'), (2, DEFAULT), ((1 + 2), 'c');`,
+		},
+		{
+			source: "INSERT INTO users DEFAULT VALUES",
+			result: `INSERT INTO users DEFAULT VALUES;`,
+		},
+		{
+			source: "INSERT INTO archived (id) SELECT id FROM users WHERE active = false",
+			result: `INSERT INTO archived (id)
+SELECT
+	id
+FROM
+	users
+WHERE
+	(active = false);`,
+		},
+		{
+			source: "INSERT INTO users (id, name) VALUES (1, 'ann') ON CONFLICT (id) DO UPDATE SET name = excluded.name, visits = users.visits + 1 WHERE users.active = true",
+			result: `INSERT INTO users (id, name) VALUES (1, 'ann') ON CONFLICT (id) DO UPDATE SET name = excluded.name, visits = (users.visits + 1) WHERE (users.active = true);`,
+		},
+		{
+			source: "INSERT INTO users DEFAULT VALUES ON CONFLICT DO NOTHING",
+			result: `INSERT INTO users DEFAULT VALUES ON CONFLICT DO NOTHING;`,
+		},
+		{
+			source: "INSERT INTO users VALUES (1) ON CONFLICT ON CONSTRAINT users_pkey DO NOTHING",
+			result: `INSERT INTO users VALUES (1) ON CONFLICT ON CONSTRAINT users_pkey DO NOTHING;`,
+		},
+		{
+			source:  "INSERT INTO users (id, name) VALUES (1, 'ann') ON DUPLICATE KEY UPDATE name = 'ann'",
+			dialect: MySQLDialect,
+			result:  `INSERT INTO users (id, name) VALUES (1, 'ann') ON DUPLICATE KEY UPDATE name = 'ann';`,
+		},
+		{
+			source:  "INSERT INTO users (id, name, visits) VALUES (1, 'ann', 1) ON DUPLICATE KEY UPDATE name = VALUES(name), visits = visits + values(visits)",
+			dialect: MySQLDialect,
+			result:  `INSERT INTO users (id, name, visits) VALUES (1, 'ann', 1) ON DUPLICATE KEY UPDATE name = VALUES(name), visits = (visits + VALUES(visits));`,
+		},
+		{
+			source: "INSERT INTO users (name) VALUES ('ann') RETURNING id, name AS n",
+			result: `INSERT INTO users (name) VALUES ('ann') RETURNING id, name AS n;`,
+		},
+		{
+			source: "INSERT INTO users (id) VALUES (1) ON CONFLICT (id) DO UPDATE SET id = excluded.id WHERE users.id = 1 RETURNING *",
+			result: `INSERT INTO users (id) VALUES (1) ON CONFLICT (id) DO UPDATE SET id = excluded.id WHERE (users.id = 1) RETURNING *;`,
+		},
+		{
+			source: "UPDATE users SET name = 'bob', visits = visits + 1 WHERE id = 1 RETURNING visits",
+			result: `UPDATE users SET name = 'bob', visits = (visits + 1) WHERE (id = 1) RETURNING visits;`,
+		},
+		{
+			source: "UPDATE users SET active = false",
+			result: `UPDATE users SET active = false;`,
+		},
+		{
+			source: "DELETE FROM users WHERE id = 1 RETURNING *",
+			result: `DELETE FROM users WHERE (id = 1) RETURNING *;`,
+		},
+		{
+			source: "DELETE FROM users",
+			result: `DELETE FROM users;`,
+		},
+		{
+			source: "SELECT id AS key_id FROM users",
+			result: `SELECT
+	id AS key_id
+FROM
+	users;`,
+		},
+		{
+			source: "BEGIN",
+			result: "BEGIN;",
+		},
+		{
+			source: "begin transaction isolation level read committed, read only",
+			result: "BEGIN TRANSACTION ISOLATION LEVEL READ COMMITTED READ ONLY;",
+		},
+		{
+			source: "START TRANSACTION READ WRITE ISOLATION LEVEL SERIALIZABLE",
+			result: "START TRANSACTION ISOLATION LEVEL SERIALIZABLE READ WRITE;",
+		},
+		{
+			source: "COMMIT",
+			result: "COMMIT;",
+		},
+		{
+			source: "ROLLBACK",
+			result: "ROLLBACK;",
+		},
+		{
+			source: "ROLLBACK TO before_update",
+			result: `ROLLBACK TO SAVEPOINT before_update;`,
+		},
+		{
+			source: "SAVEPOINT before_update",
+			result: `SAVEPOINT before_update;`,
+		},
+		{
+			source: "RELEASE SAVEPOINT before_update",
+			result: `RELEASE SAVEPOINT before_update;`,
+		},
+		{
+			source: "SELECT users.id, users.*, public.users.name FROM app.public.users WHERE users.id = 1",
+			result: `SELECT
+	users.id,
+	users.*,
+	public.users.name
+FROM
+	app.public.users
+WHERE
+	(users.id = 1);`,
+		},
+		{
+			source: `CREATE TABLE "Sales Data".orders (id INT PRIMARY KEY)`,
+			result: `CREATE TABLE "Sales Data".orders (
+	id INT PRIMARY KEY
+);`,
+		},
+		{
+			source: `SELECT "UserId", userid, "from", "a""b" FROM "Users"`,
+			result: `SELECT
+	"UserId",
+	userid,
+	"from",
+	"a""b"
+FROM
+	"Users";`,
+		},
+		{
+			source: "INSERT INTO t VALUES (E'a\\tb', $$it's$$, $body$ $$ $body$, x'ff', B'101', n'zoë', 'plain')",
+			result: `INSERT INTO t VALUES (E'a\tb', $$it's$$, $body$ $$ $body$, X'ff', B'101', N'zoë', 'plain');`,
+		},
+		{
+			source: "SELECT id FROM events WHERE created = date '2024-01-01' OR at = TIMESTAMP '2024-01-01 10:00:00' OR t = time '10:00'",
+			result: `SELECT
+	id
+FROM
+	events
+WHERE
+	(((created = DATE '2024-01-01') or (at = TIMESTAMP '2024-01-01 10:00:00')) or (t = TIME '10:00'));`,
+		},
+		{
+			source: "SELECT interval '1 year 2 days' AS span, INTERVAL '3' day, INTERVAL '3' days",
+			result: `SELECT
+	INTERVAL '1 year 2 days' AS span,
+	INTERVAL '3' DAY,
+	INTERVAL '3' AS days;`,
+		},
+		{
+			source:  "SELECT INTERVAL -3 DAY",
+			dialect: MySQLDialect,
+			result: `SELECT
+	INTERVAL '-3' DAY;`,
+		},
+		{
+			source: "SELECT date, time FROM events",
+			result: `SELECT
+	"date",
+	"time"
+FROM
+	events;`,
+		},
+		{
+			source: "SELECT id FROM users WHERE active = true LIMIT 10 OFFSET 20",
+			result: `SELECT
+	id
+FROM
+	users
+WHERE
+	(active = true)
+LIMIT 10
+OFFSET 20;`,
+		},
+		{
+			source:  "SELECT id FROM users LIMIT 20, 10",
+			dialect: MySQLDialect,
+			result: `SELECT
+	id
+FROM
+	users
+LIMIT 10
+OFFSET 20;`,
+		},
+		{
+			source:  "SELECT id FROM users OFFSET 20 ROWS FETCH FIRST 10 ROWS ONLY",
+			dialect: ANSIDialect,
+			result: `SELECT
+	id
+FROM
+	users
+LIMIT 10
+OFFSET 20;`,
+		},
+		{
+			source: "SELECT id FROM users FETCH NEXT 1 ROW ONLY",
+			result: `SELECT
+	id
+FROM
+	users
+LIMIT 1;`,
+		},
+		{
+			source:  "SELECT `Order`, \"it's \\\"x\\\"\", 'a\\'b' FROM t",
+			dialect: MySQLDialect,
+			result: `SELECT
+	"Order",
+	'it''s "x"',
+	'a''b'
+FROM
+	t;`,
+		},
+		{
+			source:  "SELECT [Id], `name` FROM \"Users\" LIMIT 5",
+			dialect: SQLiteDialect,
+			result: `SELECT
+	"Id",
+	name
+FROM
+	"Users"
+LIMIT 5;`,
+		},
+		{
+			source: "INSERT INTO audit.events VALUES (1)",
+			result: `INSERT INTO audit.events VALUES (1);`,
+		},
+		{
+			source: "CREATE INDEX age_idx ON public.users (age)",
+			result: `CREATE INDEX age_idx ON public.users (age);`,
+		},
+		{
+			source: "DROP INDEX public.age_idx",
+			result: `DROP INDEX public.age_idx;`,
+		},
+		{
+			source: "DROP TABLE public.users",
+			result: `DROP TABLE public.users;`,
+		},
+		{
+			source: "SELECT id user_id, name AS n, level lvl, u.* FROM users u WHERE u.level = 1",
+			result: `SELECT
+	id AS user_id,
+	name AS n,
+	"level" AS lvl,
+	u.*
+FROM
+	users AS u
+WHERE
+	(u."level" = 1);`,
+		},
+		{
+			source: "SELECT s.key FROM app.settings AS s",
+			result: `SELECT
+	s."key"
+FROM
+	app.settings AS s;`,
+		},
+		{
+			source: "UPDATE settings SET key = 'theme', first = true",
+			result: `UPDATE settings SET "key" = 'theme', "first" = true;`,
+		},
+		{
+			source: "SELECT score - 1, -2 FROM users WHERE id = $1 AND name <> :name AND deleted = NULL",
+			result: `SELECT
+	(score - 1),
+	-2
+FROM
+	users
+WHERE
+	(((id = $1) and (name <> :name)) and (deleted = NULL));`,
+		},
+		{
+			source: "SELECT 1 - 2 - 3, a - b + c, a || b || c FROM t WHERE a = b + 1 OR c = 2 AND d = 3",
+			result: `SELECT
+	((1 - 2) - 3),
+	((a - b) + c),
+	((a || b) || c)
+FROM
+	t
+WHERE
+	((a = (b + 1)) or ((c = 2) and (d = 3)));`,
+		},
+		{
+			source: "SELECT id FROM t WHERE a IN (1, 2) AND b not in ('x') OR c + 1 IN (d, e || f) = true",
+			result: `SELECT
+	id
+FROM
+	t
+WHERE
+	(((a IN (1, 2)) and (b NOT IN ('x'))) or (((c + 1) IN (d, (e || f))) = true));`,
+		},
+		{
+			source: "SELECT a - (b - c) FROM t WHERE a = 1 AND b = 2 OR c = (d OR e)",
+			result: `SELECT
+	(a - (b - c))
+FROM
+	t
+WHERE
+	(((a = 1) and (b = 2)) or (c = (d or e)));`,
+		},
+		{
+			source: "SELECT Größe, \"Größe\" FROM Straßen WHERE ΌΝΟΜΑ = 'Ζωή'",
+			result: `SELECT
+	größe,
+	"Größe"
+FROM
+	straßen
+WHERE
+	(όνομα = 'Ζωή');`,
+		},
+	}
+
+	for _, test := range tests {
+		parser := Parser{HelpMessagesDisabled: true, Dialect: test.dialect}
+		ast, err := parser.Parse(test.source)
+		assert.Nil(t, err, test.source)
+		if assert.NotNil(t, ast, test.source) && assert.Len(t, ast.Statements, 1, test.source) {
+			assert.Equal(t, test.result, ast.Statements[0].GenerateCode(), test.source)
+		}
+	}
+}
+
+func TestParser_ParseComments(t *testing.T) {
+	source := `-- deactivate stale users
+UPDATE users SET active = false; /* keep */
+/* cleanup */ DELETE FROM sessions`
+
+	parser := Parser{HelpMessagesDisabled: true}
+	ast, err := parser.Parse(source)
+	assert.Nil(t, err)
+	assert.Len(t, ast.Statements, 2)
+
+	parser.KeepComments = true
+	ast, err = parser.Parse(source)
+	assert.Nil(t, err)
+	if assert.Len(t, ast.Statements, 2) {
+		assert.Equal(t, `DELETE FROM sessions;`, ast.Statements[1].GenerateCode())
+		assert.Equal(t, []string{"-- deactivate stale users"}, ast.Statements[0].LeadingComments)
+		assert.Equal(t, []string{"/* keep */"}, ast.Statements[0].TrailingComments)
+		assert.Equal(t, []string{"/* cleanup */"}, ast.Statements[1].LeadingComments)
+		assert.Nil(t, ast.Statements[1].TrailingComments)
+	}
+
+	ast, err = parser.Parse(`SELECT a, -- first
+	/* second */ b FROM t /* third */ ; -- fourth
+-- fifth`)
+	assert.Nil(t, err)
+	if assert.Len(t, ast.Statements, 1) {
+		// comments within the statement stay with their tokens
+		assert.Nil(t, ast.Statements[0].LeadingComments)
+		assert.Equal(t, []string{"-- fourth", "-- fifth"}, ast.Statements[0].TrailingComments)
+	}
+}
+
+func TestParser_ParseErrors(t *testing.T) {
+	tests := []struct {
+		source  string
+		dialect Dialect
+	}{
+		{
+			source:  "INSERT INTO users VALUES (1) ON DUPLICATE KEY UPDATE id = 2",
+			dialect: PostgreSQLDialect,
+		},
+		{
+			source:  "INSERT INTO users VALUES (1) ON CONFLICT DO NOTHING",
+			dialect: MySQLDialect,
+		},
+		{
+			source: "SELECT id FROM users WHERE id IN ()",
+		},
+		{
+			source: "SELECT id FROM users WHERE id IN (DEFAULT)",
+		},
+		{
+			source: "SELECT id FROM users WHERE id IN 1",
+		},
+		{
+			source: "SELECT id FROM users WHERE id IN (1, 2",
+		},
+		{
+			source:  "INSERT INTO users VALUES (1) ON DUPLICATE KEY UPDATE id = VALUES(1)",
+			dialect: MySQLDialect,
+		},
+		{
+			source:  "INSERT INTO users VALUES (1) ON DUPLICATE KEY UPDATE id = VALUES(id",
+			dialect: MySQLDialect,
+		},
+		{
+			source: "INSERT INTO users VALUES (1) ON CONFLICT DO",
+		},
+		{
+			source: "DELETE FROM users RETURNING",
+		},
+		{
+			source: "BEGIN ISOLATION LEVEL READ",
+		},
+		{
+			source: "START",
+		},
+		{
+			source: "SELECT id FROM public.",
+		},
+		{
+			source: "DROP TABLE public.*",
+		},
+		{
+			source: "SELECT id select FROM users",
+		},
+		{
+			source: "SELECT id FROM users where",
+		},
+		{
+			source: "SELECT id AS FROM users",
+		},
+		{
+			source:  "SELECT id FROM users LIMIT 5",
+			dialect: ANSIDialect,
+		},
+		{
+			source: "SELECT id FROM users LIMIT 5, 10",
+		},
+		{
+			source: "SELECT id FROM users LIMIT",
+		},
+		{
+			source: "SELECT id FROM users FETCH FIRST 5 ROWS",
+		},
+		{
+			source:  "INSERT INTO users VALUES (1) RETURNING id",
+			dialect: MySQLDialect,
+		},
+		{
+			source:  "SELECT E'x'",
+			dialect: MySQLDialect,
+		},
+		{
+			source: "SELECT `id` FROM users",
+		},
+		{
+			source: "SELECT DATE '2024-13-01'",
+		},
+		{
+			source: "SELECT TIMESTAMP 'yesterday'",
+		},
+		{
+			source: "SELECT INTERVAL '3 fortnights'",
+		},
+		{
+			source: "SELECT INTERVAL 3 DAY",
+		},
+		{
+			source:  "SELECT INTERVAL 3",
+			dialect: MySQLDialect,
+		},
+	}
+
+	for _, test := range tests {
+		parser := Parser{HelpMessagesDisabled: true, Dialect: test.dialect}
+		_, err := parser.Parse(test.source)
+		assert.NotNil(t, err, test.source)
+	}
+}
+
+func TestNearMessage(t *testing.T) {
+	tokens, err := lex("SELECT id\nFROM users WHERE")
+	assert.Nil(t, err)
+
+	assert.Equal(t, "[2,1]: Expected comma, near: from", nearMessage(tokens, 1, "Expected comma"))
+	assert.Equal(t, "[2,12]: Expected WHERE conditionals, near: where", nearMessage(tokens, 4, "Expected WHERE conditionals"))
+}
+
+func TestParser_ParseScript(t *testing.T) {
+	source := `BEGIN;
+UPDATE users SET active = false WHERE id = 1;
+SAVEPOINT deactivated;
+DELETE FROM sessions WHERE user_id = 1;
+ROLLBACK TO SAVEPOINT deactivated;
+COMMIT;`
+
+	parser := Parser{HelpMessagesDisabled: true}
+	ast, err := parser.Parse(source)
+	assert.Nil(t, err)
+
+	kinds := []AstKind{BeginKind, UpdateKind, SavepointKind, DeleteKind, RollbackKind, CommitKind}
+	if assert.Len(t, ast.Statements, len(kinds)) {
+		for i, kind := range kinds {
+			assert.Equal(t, kind, ast.Statements[i].Kind)
+		}
 	}
 }
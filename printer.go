@@ -0,0 +1,454 @@
+package gosqlshell
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// KeywordCase selects how a Printer writes keywords
+type KeywordCase uint
+
+const (
+	// DefaultCase writes keywords like GenerateCode does, in upper case but
+	// for the and and or operators and the true and false constants
+	DefaultCase KeywordCase = iota
+	// UpperCase writes every keyword in upper case
+	UpperCase
+	// LowerCase writes every keyword in lower case
+	LowerCase
+)
+
+// Printer renders the Ast in a configurable layout. Its zero value prints
+// exactly like GenerateCode.
+type Printer struct {
+	// Dialect to print for, Print failing on constructs it can't express
+	Dialect     Dialect
+	KeywordCase KeywordCase
+	// IndentWidth is the number of spaces to indent with, tabs being used
+	// when it's 0
+	IndentWidth int
+	// Width is the number of columns lines are wrapped at, tabs counting as
+	// 4 columns. Lines are never wrapped when it's 0.
+	Width int
+	// CommaFirst starts the items of wrapped lists with their comma rather
+	// than ending the previous item with it
+	CommaFirst bool
+	// MinimalParentheses only parenthesizes the operands of expressions
+	// where operator precedence requires it
+	MinimalParentheses bool
+	// Compact prints every statement on a single line
+	Compact bool
+	// Comments prints the comments kept on statements when parsing with
+	// KeepComments, along with the blank lines between statements
+	Comments bool
+}
+
+// tabWidth is the number of columns a tab counts for when wrapping lines
+const tabWidth = 4
+
+// softBreak marks where a line may be wrapped, it's only emitted when
+// wrapping lines
+const softBreak = "\x00"
+
+// Print renders node, which must be an *Ast, a *Statement, a statement
+// such as a *SelectStatement or an expression
+func (p Printer) Print(node Node) (string, error) {
+	g := &generator{dialect: p.Dialect, printer: p}
+
+	var code string
+	switch n := node.(type) {
+	case *Ast:
+		for i, stmt := range n.Statements {
+			if i > 0 {
+				code += "\n"
+				// blank lines separating statements are kept with the comments
+				if g.printer.Comments && len(stmt.tokens) > 0 && stmt.tokens[0].blankLine {
+					code += "\n"
+				}
+			}
+			code += g.generateStatement(stmt)
+		}
+	case *Statement:
+		code = g.generateStatement(n)
+	default:
+		stmt, isStatement := statementOf(node)
+		exp, isExpression := node.(Expression)
+		switch {
+		case isStatement:
+			code = stmt.generateCode(g)
+		case isExpression && expressionNodes[reflect.TypeOf(node).Elem().Name()]:
+			g.numberParameters(exp)
+			code = exp.generateCode(g)
+		default:
+			return "", fmt.Errorf("Unable to print %T", node)
+		}
+	}
+
+	if g.err != nil {
+		return "", g.err
+	}
+
+	return g.wrap(code), nil
+}
+
+// generateStatement adds the comments of stmt to its code when printing
+// them, comments following a line comment going on their own line
+func (g *generator) generateStatement(stmt *Statement) string {
+	code := stmt.generateCode(g)
+	if !g.printer.Comments {
+		return code
+	}
+
+	if len(stmt.tokens) > 0 {
+		code = g.placeComments(code, stmt.tokens)
+	}
+
+	leading := ""
+	for _, c := range stmt.LeadingComments {
+		leading += c + "\n"
+	}
+
+	for i, c := range stmt.TrailingComments {
+		if i > 0 && strings.HasPrefix(stmt.TrailingComments[i-1], "--") {
+			code += "\n" + c
+		} else {
+			code += " " + c
+		}
+	}
+
+	return leading + code
+}
+
+// placeComments puts the comments within a statement back next to their
+// tokens, found again among the tokens lexed from its code. The comments of
+// a token missing from the code go with the token before it.
+func (g *generator) placeComments(code string, source []*token) string {
+	// the soft breaks are a byte long, as the spaces replacing them
+	generated, err := lexWithOptions(strings.ReplaceAll(code, softBreak, " "), lexOptions{dialect: g.dialect})
+	if err != nil {
+		generated = nil
+	}
+
+	match := matchTokens(source, generated)
+	leading := make([][]string, len(generated))
+	trailing := make([][]string, len(generated))
+	var unplaced []string
+	prev := -1
+	for i, t := range source {
+		var before, after []string
+		if i > 0 {
+			before = t.leading
+		}
+		if i < len(source)-1 {
+			after = t.trailing
+		}
+
+		switch j := match[i]; {
+		case j >= 0:
+			leading[j] = append(append(leading[j], unplaced...), before...)
+			trailing[j] = append(trailing[j], after...)
+			unplaced = nil
+			prev = j
+		case prev >= 0:
+			trailing[prev] = append(append(trailing[prev], before...), after...)
+		default:
+			unplaced = append(append(unplaced, before...), after...)
+		}
+	}
+
+	// from the end, so that the offsets of the tokens left stay right
+	for j := len(generated) - 1; j >= 0; j-- {
+		if len(trailing[j]) > 0 {
+			code = insertTrailingComments(code, int(generated[j].end.offset), trailing[j])
+		}
+		if len(leading[j]) > 0 {
+			code = insertLeadingComments(code, int(generated[j].loc.offset), leading[j])
+		}
+	}
+
+	for i := len(unplaced) - 1; i >= 0; i-- {
+		code = unplaced[i] + "\n" + code
+	}
+
+	return code
+}
+
+// matchTokens finds the longest common subsequence of tokens in source and
+// generated, giving the index in generated of each token of source, or -1
+func matchTokens(source, generated []*token) []int {
+	key := func(t *token) string {
+		return strings.ToLower(t.value)
+	}
+
+	lengths := make([][]int, len(source)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(generated)+1)
+	}
+	for i := len(source) - 1; i >= 0; i-- {
+		for j := len(generated) - 1; j >= 0; j-- {
+			switch {
+			case key(source[i]) == key(generated[j]):
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			case lengths[i+1][j] >= lengths[i][j+1]:
+				lengths[i][j] = lengths[i+1][j]
+			default:
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	match := make([]int, len(source))
+	i, j := 0, 0
+	for i < len(source) {
+		switch {
+		case j < len(generated) && key(source[i]) == key(generated[j]):
+			match[i] = j
+			i++
+			j++
+		case j >= len(generated) || lengths[i+1][j] >= lengths[i][j+1]:
+			match[i] = -1
+			i++
+		default:
+			j++
+		}
+	}
+
+	return match
+}
+
+func isLineComment(c string) bool {
+	return strings.HasPrefix(c, "--")
+}
+
+// lineIndentation is the whitespace starting the line of code at offset at
+func lineIndentation(code string, at int) string {
+	line := code[strings.LastIndex(code[:at], "\n")+1:]
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+// insertTrailingComments writes comments after the token ending at offset
+// at, breaking the line after a line comment if code follows on it
+func insertTrailingComments(code string, at int, comments []string) string {
+	indent := lineIndentation(code, at)
+	s := ""
+	for i, c := range comments {
+		if i > 0 && isLineComment(comments[i-1]) {
+			s += "\n" + indent + c
+		} else {
+			s += " " + c
+		}
+	}
+
+	rest := at + len(code[at:]) - len(strings.TrimLeft(code[at:], " "+softBreak))
+	if isLineComment(comments[len(comments)-1]) && rest < len(code) && code[rest] != '\n' {
+		return code[:at] + s + "\n" + indent + code[rest:]
+	}
+
+	return code[:at] + s + code[at:]
+}
+
+// insertLeadingComments writes comments before the token starting at offset
+// at, a line comment going on a line of its own
+func insertLeadingComments(code string, at int, comments []string) string {
+	indent := lineIndentation(code, at)
+	prefix := code[:at]
+	midLine := strings.TrimRight(prefix[strings.LastIndex(prefix, "\n")+1:], " \t"+softBreak) != ""
+	s := ""
+	for _, c := range comments {
+		if !isLineComment(c) {
+			s += c + " "
+			continue
+		}
+
+		if midLine {
+			if s == "" {
+				prefix = strings.TrimRight(prefix, " "+softBreak)
+			} else {
+				s = strings.TrimRight(s, " ")
+			}
+			s += "\n" + indent
+			midLine = false
+		}
+		s += c + "\n" + indent
+	}
+
+	return prefix + s + code[at:]
+}
+
+// keyword applies the keyword case to k, given as GenerateCode writes it
+func (g *generator) keyword(k string) string {
+	switch g.printer.KeywordCase {
+	case UpperCase:
+		return strings.ToUpper(k)
+	case LowerCase:
+		return strings.ToLower(k)
+	}
+
+	return k
+}
+
+func (g *generator) indentation() string {
+	if g.printer.IndentWidth > 0 {
+		return strings.Repeat(" ", g.printer.IndentWidth)
+	}
+
+	return "\t"
+}
+
+func (g *generator) softBreak() string {
+	if g.printer.Width > 0 {
+		return softBreak
+	}
+
+	return ""
+}
+
+// space separates the clauses of statements written on a single line, the
+// line being wrappable there
+func (g *generator) space() string {
+	return " " + g.softBreak()
+}
+
+// newline separates the clauses of statements laid out over several lines
+func (g *generator) newline() string {
+	if g.printer.Compact {
+		return g.space()
+	}
+
+	return "\n"
+}
+
+// list separates items by commas on a single line
+func (g *generator) list(items []string) string {
+	if g.printer.CommaFirst {
+		return strings.Join(items, g.softBreak()+", ")
+	}
+
+	return strings.Join(items, ", "+g.softBreak())
+}
+
+// indented lays items out one per line and indented, unless compact
+func (g *generator) indented(items []string) string {
+	if g.printer.Compact {
+		return g.list(items)
+	}
+
+	indent := g.indentation()
+	if g.printer.CommaFirst {
+		return indent + strings.Join(items, "\n"+indent+", ")
+	}
+
+	return indent + strings.Join(items, ",\n"+indent)
+}
+
+// columns is the display width of s
+func columns(s string) int {
+	return utf8.RuneCountInString(s) + strings.Count(s, "\t")*(tabWidth-1)
+}
+
+// wrap breaks the lines of code longer than the width at their soft breaks,
+// continuation lines being indented once more than the line they continue
+func (g *generator) wrap(code string) string {
+	if g.printer.Width <= 0 {
+		return code
+	}
+
+	lines := strings.Split(code, "\n")
+	for i, line := range lines {
+		segments := strings.Split(line, softBreak)
+		if columns(strings.Join(segments, "")) <= g.printer.Width {
+			lines[i] = strings.Join(segments, "")
+			continue
+		}
+
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))] + g.indentation()
+		wrapped := []string{}
+		current := segments[0]
+		for _, segment := range segments[1:] {
+			if columns(current+segment) <= g.printer.Width {
+				current += segment
+				continue
+			}
+
+			wrapped = append(wrapped, strings.TrimRight(current, " "))
+			current = indent + strings.TrimLeft(segment, " ")
+		}
+
+		lines[i] = strings.Join(append(wrapped, current), "\n")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// precedence of binary operators in SQL, 0 for unknown ones
+func precedence(operator string) uint {
+	switch operator {
+	case string(orKeyword):
+		return 1
+	case string(andKeyword):
+		return 2
+	case string(eqSymbol), string(neqSymbol), string(inKeyword):
+		return 3
+	case string(concatSymbol), string(plusSymbol), string(minusSymbol):
+		return 4
+	}
+
+	return 0
+}
+
+// associative operators give the same result however a chain of them is
+// grouped
+var associative = map[string]bool{
+	string(andKeyword):   true,
+	string(orKeyword):    true,
+	string(concatSymbol): true,
+}
+
+// generateOperand parenthesizes the operand of operator if its precedence
+// needs it. Operators of equal precedence group to the left, so right operands
+// of equal precedence are parenthesized unless they are the same
+// associative operator.
+func (g *generator) generateOperand(operator string, operand Expression, right bool) string {
+	s := operand.generateCode(g)
+	if !g.printer.MinimalParentheses {
+		return s
+	}
+
+	var childOperator string
+	switch child := operand.(type) {
+	case *BinaryExpression:
+		// MySQL's CONCAT() is a function call
+		if child.Operator == string(concatSymbol) && g.dialect == MySQLDialect {
+			return s
+		}
+
+		childOperator = child.Operator
+	case *InExpression:
+		childOperator = string(inKeyword)
+	default:
+		return s
+	}
+
+	p, cp := precedence(operator), precedence(childOperator)
+	if p == 0 || cp == 0 || cp < p {
+		return "(" + s + ")"
+	}
+
+	if cp == p && right && !(operator == childOperator && associative[operator]) {
+		return "(" + s + ")"
+	}
+
+	return s
+}
+
+// parenthesize wraps binary expressions in parentheses, unless they are
+// only added where needed by generateOperand
+func (g *generator) parenthesize(s string) string {
+	if g.printer.MinimalParentheses {
+		return s
+	}
+
+	return "(" + s + ")"
+}
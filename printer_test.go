@@ -0,0 +1,351 @@
+package gosqlshell
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrinter_Print(t *testing.T) {
+	tests := []struct {
+		printer Printer
+		dialect Dialect
+		source  string
+		result  string
+	}{
+		{
+			printer: Printer{KeywordCase: UpperCase},
+			source:  "select id from users where id = 1 and active = true or name = null",
+			result: `SELECT
+	id
+FROM
+	users
+WHERE
+	(((id = 1) AND (active = TRUE)) OR (name = NULL));`,
+		},
+		{
+			printer: Printer{KeywordCase: LowerCase, IndentWidth: 2},
+			source:  "SELECT id, DATE '2024-01-01' AS d FROM users LIMIT 5 OFFSET 10",
+			result: `select
+  id,
+  date '2024-01-01' as d
+from
+  users
+limit 5
+offset 10;`,
+		},
+		{
+			printer: Printer{CommaFirst: true},
+			source:  "CREATE TABLE users (id INT PRIMARY KEY, name TEXT, age INT)",
+			result: `CREATE TABLE users (
+	id INT PRIMARY KEY
+	, name TEXT
+	, age INT
+);`,
+		},
+		{
+			printer: Printer{MinimalParentheses: true},
+			source:  "SELECT a + b - c, a + (b - c) FROM t WHERE a = 1 AND (b = 2 OR c = 3) AND d || e = f",
+			result: `SELECT
+	a + b - c,
+	a + (b - c)
+FROM
+	t
+WHERE
+	a = 1 and (b = 2 or c = 3) and d || e = f;`,
+		},
+		{
+			printer: Printer{MinimalParentheses: true},
+			source:  "CREATE INDEX i ON t (a, (b + 1))",
+			result:  `CREATE INDEX i ON t (a, (b + 1));`,
+		},
+		{
+			printer: Printer{Compact: true},
+			source:  "SELECT id, name FROM users WHERE id = 1 LIMIT 1",
+			result:  `SELECT id, name FROM users WHERE (id = 1) LIMIT 1;`,
+		},
+		{
+			printer: Printer{Compact: true, MinimalParentheses: true, KeywordCase: LowerCase},
+			source:  "CREATE VIEW v AS SELECT a FROM t WHERE b = 1; CREATE TABLE t (a INT)",
+			result: `create view v as select a from t where b = 1;
+create table t (a int);`,
+		},
+		{
+			printer: Printer{Width: 40},
+			source:  "INSERT INTO users (id, name, email, created) VALUES (1, 'alice', 'alice@example.com', DEFAULT) RETURNING id",
+			result: `INSERT INTO users (id, name, email,
+	created) VALUES (1, 'alice',
+	'alice@example.com', DEFAULT)
+	RETURNING id;`,
+		},
+		{
+			printer: Printer{Width: 40, CommaFirst: true},
+			source:  "UPDATE users SET name = 'alice', email = 'alice@example.com' WHERE id = 1",
+			result: `UPDATE users SET name = 'alice'
+	, email = 'alice@example.com'
+	WHERE (id = 1);`,
+		},
+		{
+			printer: Printer{Width: 30, IndentWidth: 4},
+			source:  "SELECT id FROM users WHERE name = 'alice' AND email = 'alice@example.com' OR id = 1",
+			result: `SELECT
+    id
+FROM
+    users
+WHERE
+    (((name = 'alice')
+        and (email = 'alice@example.com'))
+        or (id = 1));`,
+		},
+		{
+			printer: Printer{Width: 30, Compact: true, MinimalParentheses: true},
+			source:  "SELECT id, name FROM users WHERE id = 1 AND name = 'alice'",
+			result: `SELECT id, name FROM users
+	WHERE id = 1
+	and name = 'alice';`,
+		},
+		{
+			printer: Printer{Dialect: SQLServerDialect, KeywordCase: LowerCase, MinimalParentheses: true},
+			dialect: PostgreSQLDialect,
+			source:  "SELECT a || b, TIMESTAMP '2024-01-01 10:00:00' FROM t LIMIT 5",
+			result: `select top (5)
+	a + b,
+	cast('2024-01-01 10:00:00' as datetime2)
+from
+	t;`,
+		},
+	}
+
+	for _, test := range tests {
+		parser := Parser{HelpMessagesDisabled: true, Dialect: test.dialect}
+		ast, err := parser.Parse(test.source)
+		if !assert.Nil(t, err, test.source) {
+			continue
+		}
+
+		code, err := test.printer.Print(ast)
+		assert.Nil(t, err, test.source)
+		assert.Equal(t, test.result, code, test.source)
+	}
+}
+
+func TestPrinter_Print_default(t *testing.T) {
+	source := `SELECT a AS x FROM t WHERE a = 1 OR b <> 'c' LIMIT 2;
+CREATE TABLE t (a INT PRIMARY KEY);
+CREATE UNIQUE INDEX i ON t USING btree (a DESC, (a + 1)) INCLUDE (b) WHERE a = 1;
+CREATE VIEW v (a) AS SELECT a FROM t;
+INSERT INTO t (a) VALUES (1), (DEFAULT) ON CONFLICT (a) DO UPDATE SET a = 2 WHERE a = 3 RETURNING a;
+UPDATE t SET a = INTERVAL '1' DAY WHERE a = 1;
+DELETE FROM t WHERE a = 1;
+BEGIN ISOLATION LEVEL READ COMMITTED;
+ROLLBACK TO SAVEPOINT s`
+
+	parser := Parser{HelpMessagesDisabled: true}
+	ast, err := parser.Parse(source)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	code, err := Printer{}.Print(ast)
+	assert.Nil(t, err)
+
+	expected, err := ast.GenerateCodeFor(PostgreSQLDialect)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, code)
+}
+
+func TestPrinter_Print_comments(t *testing.T) {
+	parser := Parser{HelpMessagesDisabled: true, KeepComments: true}
+	ast, err := parser.Parse(`-- first
+SELECT a, /* inner */ b FROM t; -- after
+DELETE FROM t /* x */; /* y */ -- z
+-- last`)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	code, err := Printer{Compact: true, Comments: true}.Print(ast)
+	assert.Nil(t, err)
+	assert.Equal(t, `-- first
+SELECT a, /* inner */ b FROM t; -- after
+DELETE FROM t /* x */; /* y */ -- z
+-- last`, code)
+
+	code, err = Printer{Compact: true}.Print(ast)
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT a, b FROM t;\nDELETE FROM t;", code)
+
+	ast, err = parser.Parse(`SELECT a, -- the key
+	b FROM t; -- after
+
+-- next
+SELECT c -- only c
+FROM t WHERE /* never */ false;`)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	code, err = Printer{Comments: true}.Print(ast)
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT
+	a, -- the key
+	b
+FROM
+	t; -- after
+
+-- next
+SELECT
+	c -- only c
+FROM
+	t
+WHERE /* never */
+	false;`, code)
+
+	code, err = Printer{Compact: true, Comments: true}.Print(ast)
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT a, -- the key
+b FROM t; -- after
+
+-- next
+SELECT c -- only c
+FROM t WHERE /* never */ false;`, code)
+}
+
+func TestPrinter_Print_roundTrip(t *testing.T) {
+	sources := []string{
+		"SELECT a - b - c, (a - b) - c, a - (b - c) FROM t",
+		"SELECT id FROM t WHERE (a = 1 OR b = 2) AND c = 3 OR d = 4",
+		"SELECT id FROM t WHERE a = (b = c) AND (d || e) = f",
+		"CREATE INDEX i ON t ((a || b), c)",
+		"INSERT INTO t VALUES (a = 1 AND b = 2, 'x')",
+		"SELECT id FROM t WHERE a = b IN (1) AND c = (d NOT IN (e, f)) OR (g IN (h)) + 1 = i",
+	}
+
+	printers := []Printer{
+		{MinimalParentheses: true},
+		{MinimalParentheses: true, Compact: true, KeywordCase: LowerCase},
+		{Width: 10, CommaFirst: true, IndentWidth: 2},
+	}
+
+	for _, source := range sources {
+		parser := Parser{HelpMessagesDisabled: true}
+		ast, err := parser.Parse(source)
+		if !assert.Nil(t, err, source) {
+			continue
+		}
+
+		expected, err := ast.GenerateCodeFor(PostgreSQLDialect)
+		assert.Nil(t, err, source)
+		for _, printer := range printers {
+			code, err := printer.Print(ast)
+			if !assert.Nil(t, err, source) {
+				continue
+			}
+
+			reparsed, err := parser.Parse(code)
+			if assert.Nil(t, err, code) {
+				regenerated, err := reparsed.GenerateCodeFor(PostgreSQLDialect)
+				assert.Nil(t, err, code)
+				assert.Equal(t, expected, regenerated, code)
+			}
+		}
+	}
+}
+
+func TestPrinter_Print_nodes(t *testing.T) {
+	parser := Parser{HelpMessagesDisabled: true}
+	ast, err := parser.Parse("DELETE FROM t WHERE a = 1 RETURNING a")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	code, err := Printer{KeywordCase: LowerCase}.Print(ast.Statements[0])
+	assert.Nil(t, err)
+	assert.Equal(t, "delete from t where (a = 1) returning a;", code)
+
+	code, err = Printer{MinimalParentheses: true}.Print(ast.Statements[0].DeleteStatement.Where)
+	assert.Nil(t, err)
+	assert.Equal(t, "a = 1", code)
+
+	_, err = Printer{Dialect: MySQLDialect}.Print(ast)
+	assert.EqualError(t, err, "RETURNING is not supported by MySQL")
+
+	code, err = Printer{Compact: true}.Print(ast.Statements[0].DeleteStatement)
+	assert.Nil(t, err)
+	assert.Equal(t, "DELETE FROM t WHERE (a = 1) RETURNING a;", code)
+
+	_, err = Printer{}.Print(&SetClause{})
+	assert.EqualError(t, err, "Unable to print *gosqlshell.SetClause")
+}
+
+func TestPrinter_Print_expressionParameters(t *testing.T) {
+	parser := Parser{HelpMessagesDisabled: true, Dialect: MySQLDialect}
+	ast, err := parser.Parse("DELETE FROM t WHERE a = ? AND b = ?")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	where := ast.Statements[0].DeleteStatement.Where
+	code, err := Printer{MinimalParentheses: true}.Print(where)
+	assert.Nil(t, err)
+	assert.Equal(t, "a = $1 and b = $2", code)
+
+	statement, err := Printer{MinimalParentheses: true}.Print(ast)
+	assert.Nil(t, err)
+	assert.Contains(t, statement, code)
+
+	_, err = Printer{Dialect: SQLServerDialect}.Print(where)
+	assert.EqualError(t, err, "positional parameter ? is not supported by SQL Server")
+}
+
+func TestPrinter_Print_minimalParentheses(t *testing.T) {
+	id := func(name string) Expression { return &Identifier{Name: name} }
+	op := func(left Expression, operator string, right Expression) Expression {
+		return &BinaryExpression{Left: left, Right: right, Operator: operator}
+	}
+
+	tests := []struct {
+		exp    Expression
+		result string
+	}{
+		{op(op(id("a"), "-", id("b")), "-", id("c")), "a - b - c"},
+		{op(id("a"), "-", op(id("b"), "-", id("c"))), "a - (b - c)"},
+		{op(id("a"), "+", op(id("b"), "-", id("c"))), "a + (b - c)"},
+		{op(id("a"), "-", op(id("b"), "+", id("c"))), "a - (b + c)"},
+		{op(id("a"), "||", op(id("b"), "||", id("c"))), "a || b || c"},
+		{op(id("a"), "=", op(id("b"), "=", id("c"))), "a = (b = c)"},
+		{op(id("a"), "=", op(id("b"), "+", id("c"))), "a = b + c"},
+		{op(op(id("a"), "=", id("b")), "+", id("c")), "(a = b) + c"},
+		{op(op(id("a"), "or", id("b")), "and", id("c")), "(a or b) and c"},
+		{op(id("a"), "and", op(id("b"), "or", id("c"))), "a and (b or c)"},
+		{op(op(id("a"), "and", id("b")), "or", id("c")), "a and b or c"},
+		{op(id("a"), "or", op(id("b"), "and", id("c"))), "a or b and c"},
+		{op(id("a"), "and", op(id("b"), "and", id("c"))), "a and b and c"},
+		{op(id("a"), "=", &InExpression{Expression: id("b"), List: []Expression{id("c")}}), "a = (b IN (c))"},
+		{op(&InExpression{Expression: id("a"), List: []Expression{id("b")}}, "=", id("c")), "a IN (b) = c"},
+		{op(&InExpression{Expression: id("a"), List: []Expression{id("b")}}, "+", id("c")), "(a IN (b)) + c"},
+		{&InExpression{Expression: op(id("a"), "+", id("b")), Not: true, List: []Expression{op(id("c"), "or", id("d"))}}, "a + b NOT IN (c or d)"},
+		{&InExpression{Expression: op(id("a"), "=", id("b")), List: []Expression{id("c")}}, "a = b IN (c)"},
+		{&InExpression{Expression: op(id("a"), "and", id("b")), List: []Expression{id("c")}}, "(a and b) IN (c)"},
+	}
+
+	for _, test := range tests {
+		code, err := Printer{MinimalParentheses: true}.Print(test.exp)
+		assert.Nil(t, err, test.result)
+		assert.Equal(t, test.result, code)
+	}
+
+	// a conjunct added to a WHERE clause still filters every row
+	parser := Parser{HelpMessagesDisabled: true}
+	ast, err := parser.Parse("SELECT id FROM t WHERE x = 1")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	tenant := op(op(id("tenant"), "=", &Literal{Kind: NumericLiteral, Value: "1"}), "or", op(id("tenant"), "=", &Literal{Kind: NumericLiteral, Value: "2"}))
+	assert.Nil(t, AddWhereConjunct(ast.Statements[0], tenant))
+
+	code, err := Printer{MinimalParentheses: true, Compact: true}.Print(ast)
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE x = 1 and (tenant = 1 or tenant = 2);", code)
+}
@@ -0,0 +1,86 @@
+package gosqlshell
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RedactPolicy tells Redact how to mask a query. Its zero value masks every
+// literal and keeps identifiers.
+type RedactPolicy struct {
+	// Dialect the redacted query is generated for
+	Dialect Dialect
+	// StringMask replaces string literals of every kind, as well as the
+	// value of date, time and interval constants, "***" being used when it's
+	// empty
+	StringMask string
+	// NumericMask replaces numeric literals, "0" being used when it's empty
+	NumericMask string
+	// Identifiers maps the names of identifiers to the names replacing them,
+	// other identifiers being kept
+	Identifiers map[string]string
+}
+
+// Redact generates the code of the Ast with its literals masked, to log
+// queries without the values they hold. Booleans are masked as false, while
+// NULL, DEFAULT and parameters are kept. The Ast itself is left untouched.
+func Redact(ast *Ast, policy RedactPolicy) (string, error) {
+	stringMask := policy.StringMask
+	if stringMask == "" {
+		stringMask = "***"
+	}
+
+	numericMask := policy.NumericMask
+	if numericMask == "" {
+		numericMask = "0"
+	}
+
+	digits := strings.TrimPrefix(numericMask, "-")
+	if _, cur, ok := lexNumeric(digits, cursor{}); !ok || cur.pointer != uint(len(digits)) {
+		return "", fmt.Errorf("Numeric mask %q is not a number", numericMask)
+	}
+
+	var redacted Ast
+	if err := clone(ast, &redacted); err != nil {
+		return "", err
+	}
+
+	// string literals hold the SQL escaped form of the value
+	stringMask = strings.ReplaceAll(stringMask, "'", "''")
+	Apply(&redacted, func(c *Cursor) bool {
+		switch n := c.Node().(type) {
+		case *Literal:
+			switch n.Kind {
+			case NumericLiteral:
+				c.Replace(&Literal{Kind: NumericLiteral, Value: numericMask})
+			case BoolLiteral:
+				c.Replace(&Literal{Kind: BoolLiteral, Value: string(falseKeyword)})
+			case NullLiteral, DefaultLiteral:
+				// nothing to hide
+			default:
+				c.Replace(&Literal{Kind: StringLiteral, Value: stringMask})
+			}
+		case *TypedLiteral:
+			// the clone is ours to change, the type and unit being kept
+			if n.Value.Kind == NumericLiteral {
+				n.Value = &Literal{Kind: NumericLiteral, Value: numericMask}
+			} else {
+				n.Value = &Literal{Kind: StringLiteral, Value: stringMask}
+			}
+			n.Time = time.Time{}
+			if n.Interval != nil {
+				n.Interval = &Interval{}
+			}
+			return false
+		case *Identifier:
+			if name, ok := policy.Identifiers[n.Name]; ok {
+				c.Replace(&Identifier{Name: name, Quoted: n.Quoted})
+			}
+		}
+
+		return true
+	}, nil)
+
+	return redacted.GenerateCodeFor(policy.Dialect)
+}
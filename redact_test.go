@@ -0,0 +1,83 @@
+package gosqlshell
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		source string
+		policy RedactPolicy
+		result string
+		err    string
+	}{
+		{
+			source: "UPDATE users SET email = 'alice@example.com', age = 42, admin = true WHERE id = $1",
+			result: "UPDATE users SET email = '***', age = 0, admin = false WHERE (id = $1);",
+		},
+		{
+			source: "INSERT INTO users (name, born, bio) VALUES (N'Zoë', DATE '1990-05-01', NULL), (E'x\\ny', DEFAULT, X'FF')",
+			policy: RedactPolicy{StringMask: "it's hidden", NumericMask: "-1"},
+			result: "INSERT INTO users (name, born, bio) VALUES ('it''s hidden', DATE 'it''s hidden', NULL), ('it''s hidden', DEFAULT, 'it''s hidden');",
+		},
+		{
+			source: "SELECT TIMESTAMP '2024-01-01 10:00:00', INTERVAL '90 minutes', INTERVAL '3' DAY",
+			result: "SELECT\n\tTIMESTAMP '***',\n\tINTERVAL '***',\n\tINTERVAL '***' DAY;",
+		},
+		{
+			source: "SELECT DATE '2024-01-01' AS born",
+			policy: RedactPolicy{Dialect: SQLServerDialect},
+			result: "SELECT\n\tCAST('***' AS DATE) AS born;",
+		},
+		{
+			source: "SELECT INTERVAL '90 minutes'",
+			policy: RedactPolicy{Dialect: MySQLDialect},
+			result: "SELECT\n\tINTERVAL 0 YEAR;",
+		},
+		{
+			source: "DELETE FROM patients WHERE ssn = '123-45-6789' AND visits = 3",
+			policy: RedactPolicy{
+				Dialect:     MySQLDialect,
+				NumericMask: "9.9",
+				Identifiers: map[string]string{"patients": "t1", "ssn": "Column 1"},
+			},
+			result: "DELETE FROM t1 WHERE ((`Column 1` = '***') and (visits = 9.9));",
+		},
+		{
+			source: "SELECT 1",
+			policy: RedactPolicy{NumericMask: "NaN"},
+			err:    `Numeric mask "NaN" is not a number`,
+		},
+		{
+			source: "DELETE FROM t WHERE a = 'x' RETURNING a",
+			policy: RedactPolicy{Dialect: MySQLDialect},
+			err:    "RETURNING is not supported by MySQL",
+		},
+	}
+
+	for _, test := range tests {
+		parser := Parser{HelpMessagesDisabled: true}
+		ast, err := parser.Parse(test.source)
+		if !assert.Nil(t, err, test.source) {
+			continue
+		}
+
+		before, err := ast.GenerateCodeFor(PostgreSQLDialect)
+		assert.Nil(t, err, test.source)
+
+		code, err := Redact(ast, test.policy)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, test.source)
+			continue
+		}
+
+		assert.Nil(t, err, test.source)
+		assert.Equal(t, test.result, code, test.source)
+
+		after, err := ast.GenerateCodeFor(PostgreSQLDialect)
+		assert.Nil(t, err, test.source)
+		assert.Equal(t, before, after, test.source)
+	}
+}
@@ -0,0 +1,188 @@
+package gosqlshell
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var dateLayouts = []string{"2006-01-02"}
+
+// fractional seconds are accepted after the seconds field without being
+// part of the layout
+var timeLayouts = []string{"15:04:05", "15:04"}
+
+var timestampLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05Z07",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// Interval is the parsed value of an INTERVAL literal. Months and days are
+// kept apart from the clock time as their length depends on the date they
+// are added to.
+type Interval struct {
+	Months   int           `json:"months,omitempty"`
+	Days     int           `json:"days,omitempty"`
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+// intervalUnits holds the length of one of each unit
+var intervalUnits = map[string]Interval{
+	"year":   {Months: 12},
+	"month":  {Months: 1},
+	"week":   {Days: 7},
+	"day":    {Days: 1},
+	"hour":   {Duration: time.Hour},
+	"minute": {Duration: time.Minute},
+	"second": {Duration: time.Second},
+}
+
+func lookupIntervalUnit(name string) (Interval, bool) {
+	name = strings.ToLower(name)
+	unit, ok := intervalUnits[name]
+	if !ok && strings.HasSuffix(name, "s") {
+		unit, ok = intervalUnits[strings.TrimSuffix(name, "s")]
+	}
+
+	return unit, ok
+}
+
+func parseTime(value string, layouts []string) (time.Time, error) {
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%q is not a valid value", value)
+}
+
+// parseInterval parses intervals like '1 year 2 months', '3 days 04:05:06',
+// '-90 minutes' or '30'
+func parseInterval(value string) (*Interval, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return nil, errors.New("empty interval")
+	}
+
+	iv := Interval{}
+	for i := 0; i < len(fields); i++ {
+		if strings.Contains(fields[i], ":") {
+			d, err := parseClock(fields[i])
+			if err != nil {
+				return nil, err
+			}
+
+			iv.Duration += d
+			continue
+		}
+
+		// a trailing number without unit counts seconds
+		unit := intervalUnits["second"]
+		if i+1 < len(fields) {
+			var ok bool
+			unit, ok = lookupIntervalUnit(fields[i+1])
+			if !ok {
+				return nil, fmt.Errorf("unknown unit %s", fields[i+1])
+			}
+		}
+
+		if unit.Duration == 0 {
+			// months and days have no fixed length, so only whole
+			// numbers of them are allowed
+			n, err := strconv.Atoi(fields[i])
+			if err != nil {
+				return nil, fmt.Errorf("%s is not a whole number", fields[i])
+			}
+
+			iv.Months += n * unit.Months
+			iv.Days += n * unit.Days
+		} else {
+			n, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s is not a number", fields[i])
+			}
+
+			iv.Duration += time.Duration(n * float64(unit.Duration))
+		}
+
+		i++
+	}
+
+	return &iv, nil
+}
+
+// parseClock parses the [-]hh:mm[:ss[.fff]] part of an interval
+func parseClock(value string) (time.Duration, error) {
+	sign := time.Duration(1)
+	clock := value
+	if strings.HasPrefix(clock, "-") {
+		sign = -1
+		clock = clock[1:]
+	}
+
+	parts := strings.Split(clock, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("%s is not a valid time", value)
+	}
+
+	units := []time.Duration{time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, part := range parts {
+		var n float64
+		var err error
+		if i == 2 {
+			n, err = strconv.ParseFloat(part, 64)
+		} else {
+			var whole uint64
+			whole, err = strconv.ParseUint(part, 10, 64)
+			n = float64(whole)
+		}
+
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%s is not a valid time", value)
+		}
+
+		d += time.Duration(n * float64(units[i]))
+	}
+
+	return sign * d, nil
+}
+
+// singleUnit expresses the interval as a whole number of a single unit, as
+// needed by INTERVAL n UNIT
+func (iv Interval) singleUnit() (int64, string, bool) {
+	switch {
+	case iv.Days == 0 && iv.Duration == 0 && iv.Months%12 == 0:
+		return int64(iv.Months / 12), "YEAR", true
+	case iv.Days == 0 && iv.Duration == 0:
+		return int64(iv.Months), "MONTH", true
+	case iv.Months == 0 && iv.Duration == 0:
+		return int64(iv.Days), "DAY", true
+	case iv.Months == 0 && iv.Days == 0:
+		units := []struct {
+			length time.Duration
+			name   string
+		}{
+			{time.Hour, "HOUR"},
+			{time.Minute, "MINUTE"},
+			{time.Second, "SECOND"},
+		}
+
+		for _, unit := range units {
+			if iv.Duration%unit.length == 0 {
+				return int64(iv.Duration / unit.length), unit.name, true
+			}
+		}
+	}
+
+	return 0, "", false
+}
@@ -0,0 +1,81 @@
+package gosqlshell
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		value    string
+		interval *Interval
+	}{
+		{"3 days", &Interval{Days: 3}},
+		{"1 year 2 months", &Interval{Months: 14}},
+		{"1 WEEK", &Interval{Days: 7}},
+		{"-90 minutes", &Interval{Duration: -90 * time.Minute}},
+		{"1.5 hours", &Interval{Duration: 90 * time.Minute}},
+		{"2 days 04:05:06.5", &Interval{Days: 2, Duration: 4*time.Hour + 5*time.Minute + 6500*time.Millisecond}},
+		{"-01:30", &Interval{Duration: -90 * time.Minute}},
+		// false tests
+		{"", nil},
+		{"3 days four", nil},
+		{"30", &Interval{Duration: 30 * time.Second}},
+		{"3 days 4", &Interval{Days: 3, Duration: 4 * time.Second}},
+		{"3 fortnights", nil},
+		{"1.5 days", nil},
+		{"1:2:3:4", nil},
+		{"01:-30", nil},
+	}
+
+	for _, test := range tests {
+		iv, err := parseInterval(test.value)
+		assert.Equal(t, test.interval, iv, test.value)
+		assert.Equal(t, test.interval == nil, err != nil, test.value)
+	}
+}
+
+func TestParser_ParseTypedLiteral(t *testing.T) {
+	tests := []struct {
+		source   string
+		dialect  Dialect
+		time     time.Time
+		interval *Interval
+	}{
+		{
+			source: "SELECT DATE '2024-02-29'",
+			time:   time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			source: "SELECT TIME '10:30:15.25'",
+			time:   time.Date(0, 1, 1, 10, 30, 15, 250000000, time.UTC),
+		},
+		{
+			source: "SELECT TIMESTAMP '2024-01-01T10:00:00+02:00'",
+			time:   time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			source:   "SELECT INTERVAL '3' day",
+			interval: &Interval{Days: 3},
+		},
+		{
+			source:   "SELECT INTERVAL 2 HOUR",
+			dialect:  MySQLDialect,
+			interval: &Interval{Duration: 2 * time.Hour},
+		},
+	}
+
+	for _, test := range tests {
+		parser := Parser{HelpMessagesDisabled: true, Dialect: test.dialect}
+		ast, err := parser.Parse(test.source)
+		if !assert.Nil(t, err, test.source) {
+			continue
+		}
+
+		typed := ast.Statements[0].SelectStatement.Items[0].Expression.(*TypedLiteral)
+		assert.True(t, test.time.Equal(typed.Time), test.source)
+		assert.Equal(t, test.interval, typed.Interval, test.source)
+	}
+}
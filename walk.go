@@ -0,0 +1,216 @@
+package gosqlshell
+
+// Visitor's Visit method is invoked by Walk for each node. If the visitor w
+// it returns is not nil, Walk visits each of the children of node with w,
+// followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+func walkIdentifiers(v Visitor, ids []*Identifier) {
+	for _, id := range ids {
+		Walk(v, id)
+	}
+}
+
+func walkSelectItems(v Visitor, items []*SelectItem) {
+	for _, item := range items {
+		Walk(v, item)
+	}
+}
+
+func walkSetClauses(v Visitor, set []*SetClause) {
+	for _, sc := range set {
+		Walk(v, sc)
+	}
+}
+
+// Walk traverses the tree under node in depth first order, visiting the
+// children in the order they appear in the source. It starts by calling
+// v.Visit(node), which must not be nil. Statements are walked from their
+// *Statement, the statement of its Kind being its only child.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Ast:
+		for _, stmt := range n.Statements {
+			Walk(v, stmt)
+		}
+	case *Statement:
+		Walk(v, n.node())
+
+	// statements
+	case *SelectStatement:
+		walkSelectItems(v, n.Items)
+		if n.From != nil {
+			Walk(v, n.From)
+		}
+
+		if n.Where != nil {
+			Walk(v, n.Where)
+		}
+
+		if n.Limit != nil {
+			Walk(v, n.Limit)
+		}
+	case *CreateTableStatement:
+		Walk(v, n.Name)
+		for _, col := range n.Columns {
+			Walk(v, col)
+		}
+	case *CreateIndexStatement:
+		Walk(v, n.Name)
+		Walk(v, n.Table)
+		if n.Method != nil {
+			Walk(v, n.Method)
+		}
+
+		for _, e := range n.Elements {
+			Walk(v, e)
+		}
+
+		walkIdentifiers(v, n.Include)
+		if n.Where != nil {
+			Walk(v, n.Where)
+		}
+	case *DropIndexStatement:
+		Walk(v, n.Name)
+	case *DropTableStatement:
+		Walk(v, n.Name)
+	case *CreateViewStatement:
+		Walk(v, n.Name)
+		walkIdentifiers(v, n.Columns)
+		Walk(v, n.Query)
+	case *RefreshMaterializedViewStatement:
+		Walk(v, n.Name)
+	case *DropViewStatement:
+		Walk(v, n.Name)
+	case *InsertStatement:
+		Walk(v, n.Table)
+		walkIdentifiers(v, n.Columns)
+		for _, row := range n.Values {
+			for _, exp := range row {
+				Walk(v, exp)
+			}
+		}
+
+		if n.Query != nil {
+			Walk(v, n.Query)
+		}
+
+		if n.OnConflict != nil {
+			Walk(v, n.OnConflict)
+		}
+
+		walkSetClauses(v, n.OnDuplicateKey)
+		walkSelectItems(v, n.Returning)
+	case *UpdateStatement:
+		Walk(v, n.Table)
+		walkSetClauses(v, n.Set)
+		if n.Where != nil {
+			Walk(v, n.Where)
+		}
+
+		walkSelectItems(v, n.Returning)
+	case *DeleteStatement:
+		Walk(v, n.Table)
+		if n.Where != nil {
+			Walk(v, n.Where)
+		}
+
+		walkSelectItems(v, n.Returning)
+	case *BeginStatement, *CommitStatement:
+		// nothing to walk
+	case *RollbackStatement:
+		if n.Savepoint != nil {
+			Walk(v, n.Savepoint)
+		}
+	case *SavepointStatement:
+		Walk(v, n.Name)
+	case *ReleaseSavepointStatement:
+		Walk(v, n.Name)
+
+	// clauses
+	case *SelectItem:
+		if n.Expression != nil {
+			Walk(v, n.Expression)
+		}
+
+		if n.Alias != nil {
+			Walk(v, n.Alias)
+		}
+	case *TableReference:
+		Walk(v, n.Name)
+		if n.Alias != nil {
+			Walk(v, n.Alias)
+		}
+	case *LimitClause:
+		limit := []Expression{n.Count, n.Offset}
+		if n.Comma {
+			limit = []Expression{n.Offset, n.Count}
+		}
+
+		for _, exp := range limit {
+			if exp != nil {
+				Walk(v, exp)
+			}
+		}
+	case *ColumnDefinition:
+		Walk(v, n.Name)
+	case *IndexElement:
+		Walk(v, n.Expression)
+	case *SetClause:
+		Walk(v, n.Column)
+		Walk(v, n.Value)
+	case *OnConflictClause:
+		walkIdentifiers(v, n.Columns)
+		if n.Constraint != nil {
+			Walk(v, n.Constraint)
+		}
+
+		walkSetClauses(v, n.Set)
+		if n.Where != nil {
+			Walk(v, n.Where)
+		}
+
+	// expressions
+	case *QualifiedName:
+		walkIdentifiers(v, n.Parts)
+	case *BinaryExpression:
+		Walk(v, n.Left)
+		Walk(v, n.Right)
+	case *TypedLiteral:
+		Walk(v, n.Value)
+	case *ValuesExpression:
+		Walk(v, n.Column)
+	case *InExpression:
+		Walk(v, n.Expression)
+		for _, exp := range n.List {
+			Walk(v, exp)
+		}
+	case *Identifier, *Literal, *ParameterExpression:
+		// nothing to walk
+	}
+
+	v.Visit(nil)
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+
+	return nil
+}
+
+// Inspect traverses the tree under node in depth first order. It starts by
+// calling f(node), which must not be nil. If f returns true, Inspect goes on
+// with each of the children of node, followed by a call of f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
@@ -0,0 +1,177 @@
+package gosqlshell
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspect(t *testing.T) {
+	tests := []struct {
+		source string
+		nodes  []string
+	}{
+		{
+			source: "SELECT u.name AS n FROM users u WHERE id = $1 LIMIT 5",
+			nodes: []string{
+				"*gosqlshell.Ast",
+				"*gosqlshell.Statement",
+				"*gosqlshell.SelectStatement",
+				"*gosqlshell.SelectItem",
+				"*gosqlshell.QualifiedName",
+				"*gosqlshell.Identifier u",
+				"*gosqlshell.Identifier name",
+				"*gosqlshell.Identifier n",
+				"*gosqlshell.TableReference",
+				"*gosqlshell.QualifiedName",
+				"*gosqlshell.Identifier users",
+				"*gosqlshell.Identifier u",
+				"*gosqlshell.BinaryExpression",
+				"*gosqlshell.Identifier id",
+				"*gosqlshell.ParameterExpression",
+				"*gosqlshell.LimitClause",
+				"*gosqlshell.Literal",
+			},
+		},
+		{
+			source: "UPDATE t SET a = DATE '2024-01-01' RETURNING *",
+			nodes: []string{
+				"*gosqlshell.Ast",
+				"*gosqlshell.Statement",
+				"*gosqlshell.UpdateStatement",
+				"*gosqlshell.QualifiedName",
+				"*gosqlshell.Identifier t",
+				"*gosqlshell.SetClause",
+				"*gosqlshell.Identifier a",
+				"*gosqlshell.TypedLiteral",
+				"*gosqlshell.Literal",
+				"*gosqlshell.SelectItem",
+			},
+		},
+		{
+			source: "COMMIT",
+			nodes: []string{
+				"*gosqlshell.Ast",
+				"*gosqlshell.Statement",
+				"*gosqlshell.CommitStatement",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		parser := Parser{HelpMessagesDisabled: true}
+		ast, err := parser.Parse(test.source)
+		if !assert.Nil(t, err, test.source) {
+			continue
+		}
+
+		nodes := []string{}
+		Inspect(ast, func(n Node) bool {
+			if id, ok := n.(*Identifier); ok {
+				nodes = append(nodes, fmt.Sprintf("%T %s", n, id.Name))
+			} else if n != nil {
+				nodes = append(nodes, fmt.Sprintf("%T", n))
+			}
+
+			return true
+		})
+		assert.Equal(t, test.nodes, nodes, test.source)
+	}
+}
+
+func TestInspect_identifiers(t *testing.T) {
+	source := `CREATE TABLE t1 (c1 INT);
+CREATE INDEX i1 ON t2 USING m1 (c2, (c3 + 1)) INCLUDE (c4) WHERE c5 = 1;
+DROP INDEX i2;
+DROP TABLE t3;
+CREATE VIEW v1 (c6) AS SELECT c7 FROM t4;
+REFRESH MATERIALIZED VIEW v2;
+DROP VIEW v3;
+INSERT INTO t5 (c8) VALUES (c9) ON CONFLICT (c10) DO UPDATE SET c11 = c12 WHERE c13 = 1 RETURNING c14;
+DELETE FROM t6 WHERE c15 = 1;
+BEGIN;
+SAVEPOINT s1;
+ROLLBACK TO s2;
+RELEASE s3;
+COMMIT`
+
+	parser := Parser{HelpMessagesDisabled: true}
+	ast, err := parser.Parse(source)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	names := []string{}
+	Inspect(ast, func(n Node) bool {
+		if id, ok := n.(*Identifier); ok {
+			names = append(names, id.Name)
+		}
+
+		return true
+	})
+
+	assert.Equal(t, []string{
+		"t1", "c1",
+		"i1", "t2", "m1", "c2", "c3", "c4", "c5",
+		"i2",
+		"t3",
+		"v1", "c6", "c7", "t4",
+		"v2",
+		"v3",
+		"t5", "c8", "c9", "c10", "c11", "c12", "c13", "c14",
+		"t6", "c15",
+		"s1", "s2", "s3",
+	}, names)
+}
+
+func TestInspect_prune(t *testing.T) {
+	parser := Parser{HelpMessagesDisabled: true}
+	ast, err := parser.Parse("INSERT INTO archive SELECT id FROM users WHERE id = ? RETURNING id")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	names := []string{}
+	Inspect(ast, func(n Node) bool {
+		if id, ok := n.(*Identifier); ok {
+			names = append(names, id.Name)
+		}
+
+		_, isQuery := n.(*SelectStatement)
+		return !isQuery
+	})
+
+	assert.Equal(t, []string{"archive", "id"}, names)
+}
+
+type countingVisitor struct {
+	nodes *int
+	ends  *int
+}
+
+func (v countingVisitor) Visit(node Node) Visitor {
+	if node == nil {
+		*v.ends++
+	} else {
+		*v.nodes++
+	}
+
+	return v
+}
+
+func TestWalk(t *testing.T) {
+	parser := Parser{HelpMessagesDisabled: true}
+	ast, err := parser.Parse("SELECT a FROM b WHERE c = 1 OR d = 2")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	nodes, ends := 0, 0
+	Walk(countingVisitor{&nodes, &ends}, ast)
+
+	// Ast, Statement, SelectStatement, SelectItem, a, TableReference,
+	// QualifiedName, b and the 7 nodes of the WHERE clause
+	assert.Equal(t, 15, nodes)
+	assert.Equal(t, nodes, ends)
+}